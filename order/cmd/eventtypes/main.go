@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+
+	"order/internal/adapters/base"
+	"order/internal/application/core/domain"
+)
+
+// 列出已登记的事件和快照类型，用于核对存储中的数据
+func main() {
+	types := base.NewTypeRegistry()
+	domain.RegisterOrderTypes(types)
+
+	for _, name := range types.EventNames() {
+		fmt.Printf("event\t%s\n", name)
+	}
+	for _, name := range types.SnapshotNames() {
+		fmt.Printf("snapshot\t%s\n", name)
+	}
+}
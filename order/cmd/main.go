@@ -1,10 +1,17 @@
 package main
 
 import (
+	"order/config"
+	"order/internal/adapters/archive"
+	"order/internal/adapters/base"
+	"order/internal/adapters/expiry"
 	"order/internal/adapters/grpc"
 	"order/internal/adapters/order"
+	"order/internal/adapters/tax"
 	"order/internal/application/core"
 	"order/internal/application/core/application"
+	"order/internal/application/core/domain"
+	"order/internal/ports"
 )
 
 func main() {
@@ -15,11 +22,45 @@ func main() {
 }
 
 func initService(s *core.Service) error {
-	orderRepoAdapter := order.NewAdapter(s.AggregateStore)
+	domain.RegisterOrderTypes(s.Types)
 
-	app := application.NewApplication(orderRepoAdapter)
+	orderRepoAdapter := order.NewAdapter(s.AggregateStore, order.WithRetryPolicy(base.RetryPolicy{
+		MaxAttempts: config.GetCommandMaxAttempts(),
+		Backoff:     config.GetCommandRetryBackoff(),
+	}))
+
+	taxAdapter, err := tax.NewAdapter(config.GetTaxRateTablePath())
+	if err != nil {
+		return err
+	}
+
+	deadlineAdapter := newDeadlineAdapter(s)
+
+	appOptions := []application.ApplicationOption{application.WithOrderTTL(config.GetOrderTTL())}
+
+	var archiveAdapter *order.ArchiveAdapter
+	if s.Archive != nil {
+		archiveAdapter = order.NewArchiveAdapter(s.Archive)
+		appOptions = append(appOptions, application.WithOrderArchive(archiveAdapter))
+	}
+
+	app := application.NewApplication(orderRepoAdapter, taxAdapter, deadlineAdapter, s.Keys, appOptions...)
 
 	grpc.NewAdapter(app, s.Conn).Mount(s.GrpcServer)
 
+	s.AddWorker(expiry.NewWorker(app, deadlineAdapter, s.Conn, config.GetOrderExpiryInterval()).Run)
+
+	if archiveAfter := config.GetOrderArchiveAfter(); archiveAdapter != nil && archiveAfter > 0 {
+		s.AddWorker(archive.NewWorker(app, archiveAdapter, s.Conn, archiveAfter, config.GetOrderArchiveInterval()).Run)
+	}
+
 	return nil
 }
+
+func newDeadlineAdapter(s *core.Service) ports.OrderDeadlineRepository {
+	if s.Storage == config.MemoryStorage {
+		return order.NewMemoryDeadlineAdapter()
+	}
+
+	return order.NewDeadlineAdapter(s.Conn)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"order/internal/application/core/domain"
+)
+
+// 输出订单状态机，用于设计文档
+func main() {
+	format := flag.String("format", "dot", "output format: dot or mermaid")
+	flag.Parse()
+
+	machine := domain.OrderMachine()
+	switch *format {
+	case "dot":
+		fmt.Print(machine.DOT())
+	case "mermaid":
+		fmt.Print(machine.Mermaid())
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", *format)
+		os.Exit(2)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 )
 
 func GetDataSourceURL() string {
@@ -24,6 +25,105 @@ func GetApplicationPort() int {
 	return port
 }
 
+// GetTaxRateTablePath 税率表路径，未设置时不计税
+func GetTaxRateTablePath() string {
+	return os.Getenv("TAX_RATE_TABLE")
+}
+
+// GetOrderTTL 订单默认的确认超时时间，未设置时订单不会超时
+func GetOrderTTL() time.Duration {
+	return getDurationValue("ORDER_TTL", 0)
+}
+
+// GetOrderExpiryInterval 检查超时订单的间隔
+func GetOrderExpiryInterval() time.Duration {
+	return getDurationValue("ORDER_EXPIRY_INTERVAL", 10*time.Second)
+}
+
+// GetOrderArchiveAfter 订单结束多久后归档，未设置时不归档
+func GetOrderArchiveAfter() time.Duration {
+	return getDurationValue("ORDER_ARCHIVE_AFTER", 0)
+}
+
+// GetOrderArchiveInterval 检查待归档订单的间隔
+func GetOrderArchiveInterval() time.Duration {
+	return getDurationValue("ORDER_ARCHIVE_INTERVAL", time.Hour)
+}
+
+// GetCommandMaxAttempts 版本冲突时命令的最大执行次数，未设置时不重试
+func GetCommandMaxAttempts() int {
+	value := os.Getenv("ORDER_COMMAND_MAX_ATTEMPTS")
+	if value == "" {
+		return 1
+	}
+
+	maxAttempts, err := strconv.Atoi(value)
+	if err != nil || maxAttempts < 1 {
+		log.Fatalf("ORDER_COMMAND_MAX_ATTEMPTS: %s is invalid", value)
+	}
+
+	return maxAttempts
+}
+
+// GetCommandRetryBackoff 版本冲突重试的间隔，按重试次数线性递增
+func GetCommandRetryBackoff() time.Duration {
+	return getDurationValue("ORDER_COMMAND_RETRY_BACKOFF", 50*time.Millisecond)
+}
+
+// GetEventCodec 写入事件和快照时的编码：json 或 msgpack，默认 json
+func GetEventCodec() string {
+	codec := os.Getenv("EVENT_CODEC")
+	if codec == "" {
+		return "json"
+	}
+
+	return codec
+}
+
+const (
+	PostgresStorage = "postgres"
+	MemoryStorage   = "memory"
+	SQLiteStorage   = "sqlite"
+)
+
+// GetStorageDriver 事件、快照等数据的存储方式，memory 不连接数据库，数据随进程退出丢失，sqlite 适用于单实例部署，默认 postgres
+func GetStorageDriver() string {
+	driver := os.Getenv("STORAGE_DRIVER")
+	switch driver {
+	case "":
+		return PostgresStorage
+	case PostgresStorage, MemoryStorage, SQLiteStorage:
+		return driver
+	default:
+		log.Fatalf("STORAGE_DRIVER: %s is invalid", driver)
+		return ""
+	}
+}
+
+// GetSQLitePath STORAGE_DRIVER 为 sqlite 时的数据库文件路径
+func GetSQLitePath() string {
+	path := os.Getenv("SQLITE_PATH")
+	if path == "" {
+		return "order.db"
+	}
+
+	return path
+}
+
+func getDurationValue(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("%s: %s is invalid", key, value)
+	}
+
+	return duration
+}
+
 func getEnvironmentValue(key string) string {
 	if os.Getenv(key) == "" {
 		log.Fatalf("%s environment variable is missing.", key)
@@ -0,0 +1,92 @@
+package archive
+
+import (
+	"context"
+	"log"
+	"order/internal/adapters/base"
+	"order/internal/ports"
+	"time"
+)
+
+// pageSize 每次查询的待归档订单数
+const pageSize = 100
+
+// Worker 定期将结束超过 age 的订单移到归档存储，归档的订单仍可正常查询和处理
+type Worker struct {
+	app      ports.Application
+	archive  ports.OrderArchiveRepository
+	client   base.Client
+	age      time.Duration
+	interval time.Duration
+}
+
+func NewWorker(app ports.Application, archive ports.OrderArchiveRepository, client base.Client, age, interval time.Duration) *Worker {
+	return &Worker{
+		app:      app,
+		archive:  archive,
+		client:   client,
+		age:      age,
+		interval: interval,
+	}
+}
+
+func (w *Worker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.archiveClosed(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) archiveClosed(ctx context.Context) {
+	before := time.Now().Add(-w.age)
+	afterID := ""
+
+	for ctx.Err() == nil {
+		streams, err := w.archive.FindClosed(ctx, before, afterID, pageSize)
+		if err != nil {
+			log.Printf("error while finding closed orders: %v", err.Error())
+			return
+		}
+
+		for _, stream := range streams {
+			err = w.archiveOrder(ctx, stream)
+			if err != nil {
+				log.Printf("error while archiving order %s: %v", stream.AggregateID, err.Error())
+			}
+		}
+
+		if len(streams) < pageSize {
+			return
+		}
+
+		afterID = streams[len(streams)-1].AggregateID
+	}
+}
+
+// archiveOrder 每个订单在独立事务中处理
+func (w *Worker) archiveOrder(ctx context.Context, stream base.StreamVersion) (err error) {
+	ctx, tx := w.client.Begin(ctx)
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+
+		if tErr := tx.Commit(); tErr != nil {
+			log.Printf("error while committing the order archive transaction: %v", tErr)
+		}
+	}()
+
+	_, err = w.app.ArchiveOrder(ctx, stream.AggregateID, stream.Version)
+
+	return err
+}
@@ -7,7 +7,6 @@ type Aggregate interface {
 	ApplyEvent(event Event) error
 	ApplySnapshot(snapshot Snapshot) error
 	ToSnapshot() (Snapshot, error)
-	GetSnapshot() Snapshot
 }
 
 type AggregateBase struct {
@@ -3,15 +3,32 @@ package base
 import (
 	"fmt"
 	"github.com/google/uuid"
+	"time"
 )
 
 const aggregateNeverCommitted = 0
 
-var ErrPendingChanges = fmt.Errorf("cannot process command while pending changes exist")
+var (
+	ErrPendingChanges      = fmt.Errorf("cannot process command while pending changes exist")
+	ErrConcurrencyConflict = fmt.Errorf("aggregate was modified concurrently")
+)
 
 type AggregateRoot struct {
-	aggregate Aggregate
-	version   int
+	aggregate       Aggregate
+	version         int
+	expectedVersion *int
+	bound           loadBound
+	archived        bool // 从归档表加载
+}
+
+// loadBound 只加载不超过该版本、且在 asOf 及之前写入的事件，零值不限制
+type loadBound struct {
+	version int
+	asOf    time.Time
+}
+
+func (b loadBound) isZero() bool {
+	return b.version == 0 && b.asOf.IsZero()
 }
 
 func NewAggregateRoot(aggregate Aggregate, options ...AggregateRootOption) *AggregateRoot {
@@ -63,12 +80,12 @@ func (a AggregateRoot) ClearEvents() {
 	a.aggregate.ClearEvents()
 }
 
-func (a AggregateRoot) CommitEvents() {
+func (a *AggregateRoot) CommitEvents() {
 	a.version += len(a.aggregate.Events())
 	a.aggregate.ClearEvents()
 }
 
-func (a AggregateRoot) LoadEvent(events ...Event) error {
+func (a *AggregateRoot) LoadEvent(events ...Event) error {
 	for _, event := range events {
 		err := a.aggregate.ApplyEvent(event)
 		if err != nil {
@@ -81,7 +98,7 @@ func (a AggregateRoot) LoadEvent(events ...Event) error {
 	return nil
 }
 
-func (a AggregateRoot) LoadSnapshot(snapshot Snapshot, version int) error {
+func (a *AggregateRoot) LoadSnapshot(snapshot Snapshot, version int) error {
 	err := a.aggregate.ApplySnapshot(snapshot)
 	if err != nil {
 		return err
@@ -105,6 +122,10 @@ func (a AggregateRoot) ProcessCommand(command Command) error {
 		return ErrPendingChanges
 	}
 
+	if a.expectedVersion != nil && *a.expectedVersion != a.version {
+		return fmt.Errorf("%w: %s %s expected version %d but is %d", ErrConcurrencyConflict, a.AggregateName(), a.AggregateID(), *a.expectedVersion, a.version)
+	}
+
 	err := a.aggregate.ProcessCommand(command)
 	if err != nil {
 		return err
@@ -120,14 +141,6 @@ func (a AggregateRoot) ProcessCommand(command Command) error {
 	return nil
 }
 
-func (a AggregateRoot) GetEvent(eventName string) Event {
-	return a.aggregate.GetEvent(eventName)
-}
-
-func (a AggregateRoot) GetSnapshotType() Snapshot {
-	return a.aggregate.GetSnapshot()
-}
-
 type AggregateRootOption func(*AggregateRoot)
 
 func WithAggregateRootID(aggregateID string) AggregateRootOption {
@@ -135,3 +148,17 @@ func WithAggregateRootID(aggregateID string) AggregateRootOption {
 		r.aggregate.setID(aggregateID)
 	}
 }
+
+// WithExpectedVersion 要求聚合根在处理命令前处于指定版本，否则返回 ErrConcurrencyConflict
+func WithExpectedVersion(version int) AggregateRootOption {
+	return func(r *AggregateRoot) {
+		r.expectedVersion = &version
+	}
+}
+
+// withLoadBound 加载历史状态，超出范围的事件和快照都不会被使用
+func withLoadBound(bound loadBound) AggregateRootOption {
+	return func(r *AggregateRoot) {
+		r.bound = bound
+	}
+}
@@ -3,32 +3,64 @@ package base
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 var ErrAggregateNotFound = errors.New("aggregate not found")
 
 type AggregateRepository interface {
 	Load(ctx context.Context, aggregateID string) (*AggregateRoot, error)
+	LoadAtVersion(ctx context.Context, aggregateID string, version int) (*AggregateRoot, error)
+	LoadAsOf(ctx context.Context, aggregateID string, asOf time.Time) (*AggregateRoot, error)
 	Save(ctx context.Context, command Command, options ...AggregateRootOption) (*AggregateRoot, error)
+	Execute(ctx context.Context, aggregateID string, command Command, options ...AggregateRootOption) (*AggregateRoot, error)
+}
+
+// RetryPolicy 版本冲突时重新加载聚合根并重试命令，MaxAttempts 包含首次执行
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
 }
 
 type AggregateRootRepository struct {
 	constructor func() Aggregate
 	store       Store
+	retry       RetryPolicy
 }
 
-func NewAggregateRootRepository(constructor func() Aggregate, store Store) *AggregateRootRepository {
+func NewAggregateRootRepository(constructor func() Aggregate, store Store, options ...AggregateRootRepositoryOption) *AggregateRootRepository {
 	r := &AggregateRootRepository{
 		constructor: constructor,
 		store:       store,
+		retry:       RetryPolicy{MaxAttempts: 1},
+	}
+
+	for _, option := range options {
+		option(r)
 	}
 
 	return r
 }
 
 func (a *AggregateRootRepository) Load(ctx context.Context, aggregateID string) (*AggregateRoot, error) {
-	root := a.root(WithAggregateRootID(aggregateID))
+	return a.load(ctx, a.root(WithAggregateRootID(aggregateID)))
+}
 
+// LoadAtVersion 只重放到指定版本的事件，版本超过当前版本时返回当前状态，返回的聚合根仅用于查询
+func (a *AggregateRootRepository) LoadAtVersion(ctx context.Context, aggregateID string, version int) (*AggregateRoot, error) {
+	if version < 1 {
+		return nil, ErrAggregateNotFound
+	}
+
+	return a.load(ctx, a.root(WithAggregateRootID(aggregateID), withLoadBound(loadBound{version: version})))
+}
+
+// LoadAsOf 只重放 asOf 及之前写入（created_at）的事件，不使用快照，返回的聚合根仅用于查询
+func (a *AggregateRootRepository) LoadAsOf(ctx context.Context, aggregateID string, asOf time.Time) (*AggregateRoot, error) {
+	return a.load(ctx, a.root(WithAggregateRootID(aggregateID), withLoadBound(loadBound{asOf: asOf})))
+}
+
+func (a *AggregateRootRepository) load(ctx context.Context, root *AggregateRoot) (*AggregateRoot, error) {
 	err := a.store.Load(ctx, root)
 	if err != nil {
 		return nil, err
@@ -38,13 +70,55 @@ func (a *AggregateRootRepository) Load(ctx context.Context, aggregateID string)
 		return nil, ErrAggregateNotFound
 	}
 
-	return root, a.store.Load(ctx, root)
+	return root, nil
 }
 
 func (a *AggregateRootRepository) Save(ctx context.Context, command Command, options ...AggregateRootOption) (*AggregateRoot, error) {
-	root := a.root(options...)
+	return a.withRetry(ctx, func() (*AggregateRoot, error) {
+		root := a.root(options...)
 
-	return root, a.save(ctx, command, root)
+		return root, a.save(ctx, command, root)
+	})
+}
+
+// Execute 加载已有聚合根（快照及之后的事件），处理命令并仅追加新产生的事件
+func (a *AggregateRootRepository) Execute(ctx context.Context, aggregateID string, command Command, options ...AggregateRootOption) (*AggregateRoot, error) {
+	return a.withRetry(ctx, func() (*AggregateRoot, error) {
+		root := a.root(append([]AggregateRootOption{WithAggregateRootID(aggregateID)}, options...)...)
+
+		err := a.store.Load(ctx, root)
+		if err != nil {
+			return root, err
+		}
+
+		if root.version == aggregateNeverCommitted {
+			return root, ErrAggregateNotFound
+		}
+
+		return root, a.save(ctx, command, root)
+	})
+}
+
+// withRetry 仅在版本冲突时重试，每次重试都重新构建聚合根，调用方指定了期望版本时不重试
+func (a *AggregateRootRepository) withRetry(ctx context.Context, attempt func() (*AggregateRoot, error)) (*AggregateRoot, error) {
+	for i := 1; ; i++ {
+		root, err := attempt()
+		if err == nil || !errors.Is(err, ErrConcurrencyConflict) || i >= a.retry.MaxAttempts {
+			return root, err
+		}
+
+		if root.expectedVersion != nil {
+			return root, err
+		}
+
+		if a.retry.Backoff > 0 {
+			select {
+			case <-ctx.Done():
+				return root, ctx.Err()
+			case <-time.After(a.retry.Backoff * time.Duration(i)):
+			}
+		}
+	}
 }
 
 func (a *AggregateRootRepository) root(options ...AggregateRootOption) *AggregateRoot {
@@ -66,5 +140,18 @@ func (a *AggregateRootRepository) save(ctx context.Context, command Command, roo
 		return err
 	}
 
+	root.CommitEvents()
+
 	return nil
 }
+
+type AggregateRootRepositoryOption func(*AggregateRootRepository)
+
+// WithRetryPolicy 开启版本冲突重试，默认不重试
+func WithRetryPolicy(policy RetryPolicy) AggregateRootRepositoryOption {
+	return func(r *AggregateRootRepository) {
+		if policy.MaxAttempts > 0 {
+			r.retry = policy
+		}
+	}
+}
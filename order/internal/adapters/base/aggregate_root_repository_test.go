@@ -0,0 +1,151 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// conflictingStore 在第一次保存前写入一个并发事件，使该次保存发生版本冲突
+type conflictingStore struct {
+	Store
+	repository *AggregateRootRepository
+	conflicts  int
+	saves      int
+}
+
+func (s *conflictingStore) Save(ctx context.Context, root *AggregateRoot) error {
+	s.saves++
+	if s.conflicts > 0 {
+		s.conflicts--
+
+		_, err := s.repository.Execute(ctx, root.AggregateID(), testDeposit{Amount: 100})
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.Store.Save(ctx, root)
+}
+
+func TestSaveConflict(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			store := backend.events(WithEventTypes(testAccountTypes()))
+			repository := NewAggregateRootRepository(newTestAccount, store)
+
+			account, err := repository.Save(ctx, testOpenAccount{Owner: "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			first := NewAggregateRoot(newTestAccount(), WithAggregateRootID(account.ID()))
+			second := NewAggregateRoot(newTestAccount(), WithAggregateRootID(account.ID()))
+			for _, root := range []*AggregateRoot{first, second} {
+				err = store.Load(ctx, root)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = root.ProcessCommand(testDeposit{Amount: 1})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err = store.Save(ctx, first)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = store.Save(ctx, second)
+			if !errors.Is(err, ErrConcurrencyConflict) {
+				t.Fatalf("expected ErrConcurrencyConflict, got %v", err)
+			}
+
+			loaded, err := repository.Load(ctx, account.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if loaded.Version() != 2 || testBalance(t, loaded) != 1 {
+				t.Fatalf("expected version 2 balance 1, got version %d balance %d", loaded.Version(), testBalance(t, loaded))
+			}
+		})
+	}
+}
+
+func TestExecuteRetry(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		maxAttempts int
+		conflicts   int
+		wantErr     error
+		wantSaves   int
+		wantBalance int
+	}{
+		{name: "retries after conflict", maxAttempts: 3, conflicts: 1, wantSaves: 2, wantBalance: 110},
+		{name: "gives up after max attempts", maxAttempts: 2, conflicts: 2, wantErr: ErrConcurrencyConflict, wantSaves: 2, wantBalance: 200},
+		{name: "no retry by default", maxAttempts: 0, conflicts: 1, wantErr: ErrConcurrencyConflict, wantSaves: 1, wantBalance: 100},
+	}
+
+	for _, backend := range testBackends(t) {
+		for _, tt := range tests {
+			t.Run(backend.name+"/"+tt.name, func(t *testing.T) {
+				events := backend.events(WithEventTypes(testAccountTypes()))
+				store := &conflictingStore{Store: events, repository: NewAggregateRootRepository(newTestAccount, events)}
+				repository := NewAggregateRootRepository(newTestAccount, store, WithRetryPolicy(RetryPolicy{MaxAttempts: tt.maxAttempts}))
+
+				account, err := store.repository.Save(ctx, testOpenAccount{Owner: "alice"})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				store.conflicts = tt.conflicts
+
+				_, err = repository.Execute(ctx, account.ID(), testDeposit{Amount: 10})
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if store.saves != tt.wantSaves {
+					t.Fatalf("expected %d saves, got %d", tt.wantSaves, store.saves)
+				}
+
+				loaded, err := repository.Load(ctx, account.ID())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if testBalance(t, loaded) != tt.wantBalance {
+					t.Fatalf("expected balance %d, got %d", tt.wantBalance, testBalance(t, loaded))
+				}
+			})
+		}
+	}
+}
+
+func TestExecuteExpectedVersion(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			store := &conflictingStore{Store: backend.events(WithEventTypes(testAccountTypes()))}
+			repository := NewAggregateRootRepository(newTestAccount, store, WithRetryPolicy(RetryPolicy{MaxAttempts: 3}))
+
+			account, err := repository.Save(ctx, testOpenAccount{Owner: "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = repository.Execute(ctx, account.ID(), testDeposit{Amount: 10}, WithExpectedVersion(0))
+			if !errors.Is(err, ErrConcurrencyConflict) {
+				t.Fatalf("expected ErrConcurrencyConflict, got %v", err)
+			}
+			if store.saves != 1 {
+				t.Fatalf("expected no retry with an expected version, got %d saves", store.saves)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultArchivedEventTableName    = "events_archive"
+	DefaultArchivedSnapshotTableName = "snapshots_archive"
+	archiveSnapshotColumns           = "entity_name, entity_id, snapshot_name, snapshot_data, codec, snapshot_version, schema_version, modified_at"
+	moveRowsSQL                      = "INSERT INTO %s (%s) SELECT %s FROM %s WHERE entity_name = $1 AND entity_id = $2"
+	deleteRowsSQL                    = "DELETE FROM %s WHERE entity_name = $1 AND entity_id = $2"
+	lastEventVersionSQL              = "SELECT COALESCE(MAX(event_version), 0) FROM %s WHERE entity_name = $1 AND entity_id = $2"
+	hasEventVersionSQL               = "SELECT COUNT(*) FROM %s WHERE entity_name = $1 AND entity_id = $2 AND event_version = $3"
+	// findArchivableSQL 最后一个事件为 $5 起的事件之一且早于 $3 的聚合根，按 ID 分页
+	findArchivableSQL = `SELECT e.entity_id, e.event_version FROM %[1]s e
+WHERE e.entity_name = $1 AND e.entity_id > $2 AND e.created_at < $3 AND e.event_name IN (%[2]s)
+AND e.event_version = (SELECT MAX(event_version) FROM %[1]s WHERE entity_name = e.entity_name AND entity_id = e.entity_id)
+ORDER BY e.entity_id ASC LIMIT $4`
+)
+
+// archiveEventColumns 移动时保留全局位置、事务 ID 和写入时间，移回后在全局日志中的位置不变
+var archiveEventColumns = DialectSQL{
+	PostgresDialectName: "entity_name, entity_id, event_version, event_name, event_data, codec, metadata, global_position, transaction_id, created_at",
+	SQLiteDialectName:   "entity_name, entity_id, event_version, event_name, event_data, codec, metadata, global_position, created_at",
+}
+
+var _ Store = (*ArchiveStore)(nil)
+
+// ArchiveStore 将不再变化的聚合根的事件和快照移动到归档表，减小热存储的数据量
+//
+// 热存储中找不到的聚合根从归档表加载，归档的聚合根写入新事件前先移回热存储。
+// 归档的事件不再出现在 ReadAll 中，移回后保留原有的全局位置，已读过该位置的订阅方不会重复读取
+type ArchiveStore struct {
+	client                    Client
+	eventTableName            string
+	snapshotTableName         string
+	archivedEventTableName    string
+	archivedSnapshotTableName string
+	archived                  Store
+	next                      Store
+}
+
+// StreamVersion 聚合根及其最新版本
+type StreamVersion struct {
+	AggregateID string
+	Version     int
+}
+
+// NewArchiveStore 归档表与热存储的表结构相同，eventOptions 和 snapshotOptions 与热存储使用的一致
+func NewArchiveStore(client Client, eventOptions []EventStoreOption, snapshotOptions []SnapshotStoreOption, options ...ArchiveStoreOption) *ArchiveStore {
+	store := &ArchiveStore{
+		client:                    client,
+		eventTableName:            newEventStore(eventOptions...).tableName,
+		snapshotTableName:         newSnapshotStore(snapshotOptions...).tableName,
+		archivedEventTableName:    DefaultArchivedEventTableName,
+		archivedSnapshotTableName: DefaultArchivedSnapshotTableName,
+	}
+
+	for _, option := range options {
+		option(store)
+	}
+
+	events := NewEventStore(client, slices.Concat(eventOptions, []EventStoreOption{WithEventTableName(store.archivedEventTableName)})...)
+	snapshots := NewSnapshotStore(client, slices.Concat(snapshotOptions, []SnapshotStoreOption{WithSnapshotStoreTableName(store.archivedSnapshotTableName)})...)
+	store.archived = snapshots(events)
+
+	return store
+}
+
+func (a *ArchiveStore) Middleware(next Store) Store {
+	a.next = next
+	return a
+}
+
+// Load 热存储中不存在时从归档表加载
+func (a *ArchiveStore) Load(ctx context.Context, root *AggregateRoot) error {
+	err := a.next.Load(ctx, root)
+	if err != nil || root.version != aggregateNeverCommitted {
+		return err
+	}
+
+	err = a.archived.Load(ctx, root)
+	if err != nil {
+		return err
+	}
+
+	root.archived = root.version != aggregateNeverCommitted
+
+	return nil
+}
+
+// Save 归档的聚合根先移回热存储；聚合根在加载之后被归档时返回 ErrConcurrencyConflict，重试时从归档表加载
+func (a *ArchiveStore) Save(ctx context.Context, root *AggregateRoot) error {
+	name := root.AggregateName()
+	id := root.AggregateID()
+
+	if root.archived {
+		err := a.Restore(ctx, name, id)
+		if err != nil {
+			return err
+		}
+
+		root.archived = false
+	}
+
+	if root.Version() == aggregateNeverCommitted {
+		return a.next.Save(ctx, root)
+	}
+
+	return a.client.Transaction(ctx, func(ctx context.Context) error {
+		err := a.next.Save(ctx, root)
+		if err != nil {
+			return err
+		}
+
+		// 写入事件时已持有事件流锁，此时热存储中没有加载时的版本说明已被归档
+		var count int
+		err = a.client.QueryRow(ctx, fmt.Sprintf(hasEventVersionSQL, a.eventTableName), name, id, root.Version()).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("%w: %s %s was archived", ErrConcurrencyConflict, name, id)
+		}
+
+		return nil
+	})
+}
+
+// FindArchivable 按 ID 顺序返回 afterID 之后、最后一个事件为 lastEvents 之一且写入时间早于 before 的聚合根
+func (a *ArchiveStore) FindArchivable(ctx context.Context, name string, lastEvents []string, before time.Time, afterID string, limit int) (streams []StreamVersion, err error) {
+	if len(lastEvents) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, 0, len(lastEvents))
+	args := []any{name, afterID, a.client.Dialect().BindTime(before), limit}
+	for _, event := range lastEvents {
+		args = append(args, event)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+
+	rows, err := a.client.Query(ctx, fmt.Sprintf(findArchivableSQL, a.eventTableName, strings.Join(placeholders, ", ")), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		cErr := rows.Close()
+		if err == nil {
+			err = cErr
+		}
+	}()
+
+	for rows.Next() {
+		var stream StreamVersion
+
+		err = rows.Scan(&stream.AggregateID, &stream.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		streams = append(streams, stream)
+	}
+
+	return streams, rows.Err()
+}
+
+// Archive 将聚合根的事件和快照移动到归档表，聚合根已不在 version 时不移动并返回 false
+func (a *ArchiveStore) Archive(ctx context.Context, name, id string, version int) (archived bool, err error) {
+	err = a.client.Transaction(ctx, func(ctx context.Context) error {
+		err := lockStream(ctx, a.client, a.eventTableName, name, id)
+		if err != nil {
+			return err
+		}
+
+		var lastVersion int
+		err = a.client.QueryRow(ctx, fmt.Sprintf(lastEventVersionSQL, a.eventTableName), name, id).Scan(&lastVersion)
+		if err != nil || lastVersion != version {
+			return err
+		}
+
+		err = a.move(ctx, name, id, a.eventTableName, a.archivedEventTableName, a.snapshotTableName, a.archivedSnapshotTableName)
+		if err != nil {
+			return err
+		}
+
+		archived = true
+
+		return nil
+	})
+
+	return archived && err == nil, err
+}
+
+// Restore 将归档的事件和快照移回热存储，聚合根未归档时不做任何操作
+func (a *ArchiveStore) Restore(ctx context.Context, name, id string) error {
+	return a.client.Transaction(ctx, func(ctx context.Context) error {
+		err := lockStream(ctx, a.client, a.eventTableName, name, id)
+		if err != nil {
+			return err
+		}
+
+		return a.move(ctx, name, id, a.archivedEventTableName, a.eventTableName, a.archivedSnapshotTableName, a.snapshotTableName)
+	})
+}
+
+func (a *ArchiveStore) move(ctx context.Context, name, id, fromEvents, toEvents, fromSnapshots, toSnapshots string) error {
+	eventColumns := archiveEventColumns.For(a.client.Dialect())
+	statements := []string{
+		fmt.Sprintf(moveRowsSQL, toEvents, eventColumns, eventColumns, fromEvents),
+		fmt.Sprintf(deleteRowsSQL, fromEvents),
+		fmt.Sprintf(moveRowsSQL, toSnapshots, archiveSnapshotColumns, archiveSnapshotColumns, fromSnapshots),
+		fmt.Sprintf(deleteRowsSQL, fromSnapshots),
+	}
+
+	for _, statement := range statements {
+		err := a.client.Exec(ctx, statement, name, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type ArchiveStoreOption func(*ArchiveStore)
+
+func WithArchivedEventTableName(tableName string) ArchiveStoreOption {
+	return func(store *ArchiveStore) {
+		store.archivedEventTableName = tableName
+	}
+}
+
+func WithArchivedSnapshotTableName(tableName string) ArchiveStoreOption {
+	return func(store *ArchiveStore) {
+		store.archivedSnapshotTableName = tableName
+	}
+}
@@ -0,0 +1,229 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type testArchive struct {
+	client     Client
+	archive    *ArchiveStore
+	store      Store
+	repository *AggregateRootRepository
+}
+
+func newTestArchive(t *testing.T, options ...AggregateRootRepositoryOption) testArchive {
+	t.Helper()
+
+	client := newTestSQLiteClient(t)
+	types := testAccountTypes()
+	eventOptions := []EventStoreOption{WithEventTypes(types)}
+	snapshotOptions := []SnapshotStoreOption{WithSnapshotStoreTypes(types), WithSnapshotStoreStrategy(NewMaxChangesSnapshotStrategy(2))}
+
+	archive := NewArchiveStore(client, eventOptions, snapshotOptions)
+	store := archive.Middleware(NewSnapshotStore(client, snapshotOptions...)(NewEventStore(client, eventOptions...)))
+
+	return testArchive{
+		client:     client,
+		archive:    archive,
+		store:      store,
+		repository: NewAggregateRootRepository(newTestAccount, store, options...),
+	}
+}
+
+// open 创建账户并存入 amounts，返回账户 ID
+func (a testArchive) open(t *testing.T, amounts ...int) string {
+	t.Helper()
+
+	ctx := context.Background()
+	account, err := a.repository.Save(ctx, testOpenAccount{Owner: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, amount := range amounts {
+		_, err = a.repository.Execute(ctx, account.ID(), testDeposit{Amount: amount})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return account.ID()
+}
+
+func (a testArchive) count(t *testing.T, tableName, id string) int {
+	t.Helper()
+
+	var count int
+	err := a.client.QueryRow(context.Background(), fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE entity_id = $1", tableName), id).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return count
+}
+
+func (a testArchive) assertCounts(t *testing.T, id string, events, snapshots, archivedEvents, archivedSnapshots int) {
+	t.Helper()
+
+	got := []int{
+		a.count(t, DefaultEventTableName, id),
+		a.count(t, DefaultSnapshotTableName, id),
+		a.count(t, DefaultArchivedEventTableName, id),
+		a.count(t, DefaultArchivedSnapshotTableName, id),
+	}
+	want := []int{events, snapshots, archivedEvents, archivedSnapshots}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected events, snapshots, archived events, archived snapshots %v, got %v", want, got)
+	}
+}
+
+func TestArchiveLoadAndRestoreOnSave(t *testing.T) {
+	ctx := context.Background()
+	a := newTestArchive(t)
+	id := a.open(t, 10, 20)
+
+	archived, err := a.archive.Archive(ctx, testAccountName, id, 3)
+	if err != nil || !archived {
+		t.Fatalf("expected archived, got %v %v", archived, err)
+	}
+	a.assertCounts(t, id, 0, 0, 3, 1)
+
+	loaded, err := a.repository.Load(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.archived || loaded.Version() != 3 || testBalance(t, loaded) != 30 {
+		t.Fatalf("expected archived version 3 balance 30, got %v %d %d", loaded.archived, loaded.Version(), testBalance(t, loaded))
+	}
+
+	loaded, err = a.repository.Execute(ctx, id, testDeposit{Amount: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Version() != 4 || testBalance(t, loaded) != 35 {
+		t.Fatalf("expected version 4 balance 35, got %d %d", loaded.Version(), testBalance(t, loaded))
+	}
+	a.assertCounts(t, id, 4, 1, 0, 0)
+
+	loaded, err = a.repository.Load(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.archived || testBalance(t, loaded) != 35 {
+		t.Fatalf("expected restored balance 35, got %v %d", loaded.archived, testBalance(t, loaded))
+	}
+}
+
+func TestArchiveSkipsChangedStream(t *testing.T) {
+	ctx := context.Background()
+	a := newTestArchive(t)
+	id := a.open(t, 10)
+
+	archived, err := a.archive.Archive(ctx, testAccountName, id, 1)
+	if err != nil || archived {
+		t.Fatalf("expected not archived, got %v %v", archived, err)
+	}
+	a.assertCounts(t, id, 2, 1, 0, 0)
+}
+
+func TestArchiveStaleSave(t *testing.T) {
+	ctx := context.Background()
+	a := newTestArchive(t)
+	id := a.open(t, 10)
+
+	root := NewAggregateRoot(newTestAccount(), WithAggregateRootID(id))
+	err := a.store.Load(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archived, err := a.archive.Archive(ctx, testAccountName, id, root.Version())
+	if err != nil || !archived {
+		t.Fatalf("expected archived, got %v %v", archived, err)
+	}
+
+	err = root.ProcessCommand(testDeposit{Amount: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 热存储中已没有加载时的版本，写入的事件随事务回滚
+	err = a.store.Save(ctx, root)
+	if !errors.Is(err, ErrConcurrencyConflict) {
+		t.Fatalf("expected ErrConcurrencyConflict, got %v", err)
+	}
+	a.assertCounts(t, id, 0, 0, 2, 1)
+}
+
+func TestArchiveStaleSaveRetry(t *testing.T) {
+	ctx := context.Background()
+	a := newTestArchive(t, WithRetryPolicy(RetryPolicy{MaxAttempts: 2}))
+	id := a.open(t, 10)
+
+	// 第一次保存前归档，重试时从归档表加载并移回热存储
+	a.repository.store = &archivingStore{Store: a.store, archive: a.archive, pending: true}
+
+	loaded, err := a.repository.Execute(ctx, id, testDeposit{Amount: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Version() != 3 || testBalance(t, loaded) != 15 {
+		t.Fatalf("expected version 3 balance 15, got %d %d", loaded.Version(), testBalance(t, loaded))
+	}
+	a.assertCounts(t, id, 3, 1, 0, 0)
+}
+
+// archivingStore 在第一次保存前归档聚合根，模拟加载之后被并发归档
+type archivingStore struct {
+	Store
+	archive *ArchiveStore
+	pending bool
+}
+
+func (s *archivingStore) Save(ctx context.Context, root *AggregateRoot) error {
+	if s.pending && !root.archived {
+		s.pending = false
+
+		_, err := s.archive.Archive(ctx, root.AggregateName(), root.AggregateID(), root.Version())
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.Store.Save(ctx, root)
+}
+
+func TestFindArchivable(t *testing.T) {
+	ctx := context.Background()
+	a := newTestArchive(t)
+	deposited := a.open(t, 10)
+	opened := a.open(t)
+
+	streams, err := a.archive.FindArchivable(ctx, testAccountName, []string{"Deposited"}, time.Now().Add(time.Minute), "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(streams) != 1 || streams[0] != (StreamVersion{AggregateID: deposited, Version: 2}) {
+		t.Fatalf("expected only %s at version 2, got %v", deposited, streams)
+	}
+
+	streams, err = a.archive.FindArchivable(ctx, testAccountName, []string{"Deposited", "AccountOpened"}, time.Now().Add(-time.Minute), "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(streams) != 0 {
+		t.Fatalf("expected no streams written before the cutoff, got %v", streams)
+	}
+
+	streams, err = a.archive.FindArchivable(ctx, testAccountName, []string{"Deposited", "AccountOpened"}, time.Now().Add(time.Minute), "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(streams) != 2 || streams[0].AggregateID >= streams[1].AggregateID {
+		t.Fatalf("expected %s and %s ordered by id, got %v", deposited, opened, streams)
+	}
+}
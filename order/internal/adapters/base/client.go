@@ -2,32 +2,54 @@ package base
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"gorm.io/gorm"
 )
 
 type Client interface {
 	Exec(ctx context.Context, sql string, args ...any) error
-	Query(ctx context.Context, sql string, args ...any) (*sql.Rows, error)
-	QueryRow(ctx context.Context, sql string, args ...any) *sql.Row
+	Query(ctx context.Context, sql string, args ...any) (Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) Row
 	Migrate(tableName string, sql string) error
-	GetTx() *gorm.DB
+	MigrateColumn(tableName string, columnName string, sql string) error
+	Begin(ctx context.Context) (context.Context, Tx)
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+	Dialect() Dialect
+}
+
+type Rows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Close() error
+	Err() error
+}
+
+type Row interface {
+	Scan(dest ...any) error
+}
+
+// Tx Begin 开启的事务，Commit 或 Rollback 只应调用其中之一
+type Tx interface {
+	Commit() error
+	Rollback() error
 }
 
 var _ Client = (*sessionClient)(nil)
 
+type txKey struct{}
+
 type sessionClient struct {
-	db *gorm.DB
-	tx *gorm.DB
+	db      *gorm.DB
+	dialect Dialect
 }
 
+// NewSessionClient 按 gorm 驱动选择方言，语句中的 $n 占位符在执行前转换
 func NewSessionClient(db *gorm.DB) Client {
-	return &sessionClient{db: db}
+	return &sessionClient{db: db, dialect: DialectByName(db.Dialector.Name())}
 }
 
 func (s *sessionClient) Exec(ctx context.Context, sql string, args ...any) error {
-	db := s.tx.WithContext(ctx).Exec(sql, args...)
+	db := s.conn(ctx).Exec(s.dialect.Rebind(sql), args...)
 	if db.Error != nil {
 		return db.Error
 	}
@@ -35,12 +57,12 @@ func (s *sessionClient) Exec(ctx context.Context, sql string, args ...any) error
 	return nil
 }
 
-func (s *sessionClient) Query(ctx context.Context, sql string, args ...any) (*sql.Rows, error) {
-	return s.tx.WithContext(ctx).Raw(sql, args...).Rows()
+func (s *sessionClient) Query(ctx context.Context, sql string, args ...any) (Rows, error) {
+	return s.conn(ctx).Raw(s.dialect.Rebind(sql), args...).Rows()
 }
 
-func (s *sessionClient) QueryRow(ctx context.Context, sql string, args ...any) *sql.Row {
-	return s.tx.WithContext(ctx).Raw(sql, args...).Row()
+func (s *sessionClient) QueryRow(ctx context.Context, sql string, args ...any) Row {
+	return s.conn(ctx).Raw(s.dialect.Rebind(sql), args...).Row()
 }
 
 func (s *sessionClient) Migrate(tableName string, sql string) error {
@@ -51,8 +73,49 @@ func (s *sessionClient) Migrate(tableName string, sql string) error {
 	return nil
 }
 
-func (s *sessionClient) GetTx() *gorm.DB {
-	s.tx = s.db.Begin()
+// MigrateColumn 为已存在的表补充新增的列
+func (s *sessionClient) MigrateColumn(tableName string, columnName string, sql string) error {
+	if !s.db.Migrator().HasColumn(tableName, columnName) {
+		return s.db.Exec(fmt.Sprintf(sql, tableName)).Error
+	}
+
+	return nil
+}
+
+// Begin 开启事务，返回的 ctx 携带该事务，使用该 ctx 的操作都在同一事务中执行
+func (s *sessionClient) Begin(ctx context.Context) (context.Context, Tx) {
+	tx := s.db.WithContext(ctx).Begin()
+
+	return context.WithValue(ctx, txKey{}, tx), sessionTx{db: tx}
+}
+
+// Transaction 在事务中执行 fn，ctx 已携带事务时使用保存点，fn 返回错误只回滚到保存点
+func (s *sessionClient) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return s.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+func (s *sessionClient) Dialect() Dialect {
+	return s.dialect
+}
+
+func (s *sessionClient) conn(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+
+	return s.db.WithContext(ctx)
+}
+
+type sessionTx struct {
+	db *gorm.DB
+}
+
+func (t sessionTx) Commit() error {
+	return t.db.Commit().Error
+}
 
-	return s.tx
+func (t sessionTx) Rollback() error {
+	return t.db.Rollback().Error
 }
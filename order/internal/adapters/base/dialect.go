@@ -0,0 +1,113 @@
+package base
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	PostgresDialectName = "postgres"
+	SQLiteDialectName   = "sqlite"
+)
+
+const (
+	pgUniqueViolationCode      = "23505"
+	sqliteConstraintPrimaryKey = 1555
+	sqliteConstraintUnique     = 2067
+	// sqliteTimeLayout SQLite 以文本保存时间，固定宽度的毫秒精度 UTC 时间按字符串比较即按时间先后比较
+	sqliteTimeLayout       = "2006-01-02 15:04:05.000"
+	sqliteCurrentTimestamp = "strftime('%Y-%m-%d %H:%M:%f', 'now')"
+)
+
+// Dialect 数据库之间的 SQL 差异，语句统一按 Postgres 的 $n 占位符编写
+type Dialect interface {
+	Name() string
+	// Rebind 将语句中的 $n 占位符转换为该数据库的写法
+	Rebind(query string) string
+	// LockSQL 以 $1 为键获取事务级锁的语句，锁持有到事务结束，数据库本身串行写入时为空
+	LockSQL() string
+	// SkipLocked 附加在查询之后，锁定结果行并跳过其他事务已锁定的行，不支持行锁时为空
+	SkipLocked() string
+	// IsUniqueViolation 错误是否为唯一约束或主键冲突
+	IsUniqueViolation(err error) bool
+	// CurrentTimestamp 写入当前时间的 SQL 表达式，与 BindTime 绑定的参数精度一致，可以直接比较
+	CurrentTimestamp() string
+	// BindTime 与时间列比较时绑定的参数
+	BindTime(t time.Time) any
+}
+
+// DialectSQL 各数据库写法不同的语句，以方言名称为键
+type DialectSQL map[string]string
+
+// For 返回方言对应的语句，未提供时使用 Postgres 的语句
+func (d DialectSQL) For(dialect Dialect) string {
+	if sql, ok := d[dialect.Name()]; ok {
+		return sql
+	}
+
+	return d[PostgresDialectName]
+}
+
+// DialectByName 按 gorm 驱动名称返回方言，未知的驱动按 Postgres 处理
+func DialectByName(name string) Dialect {
+	if name == SQLiteDialectName {
+		return SQLiteDialect{}
+	}
+
+	return PostgresDialect{}
+}
+
+type PostgresDialect struct{}
+
+func (PostgresDialect) Name() string { return PostgresDialectName }
+
+func (PostgresDialect) Rebind(query string) string { return query }
+
+func (PostgresDialect) LockSQL() string { return "SELECT pg_advisory_xact_lock(hashtext($1))" }
+
+func (PostgresDialect) SkipLocked() string { return "FOR UPDATE SKIP LOCKED" }
+
+func (PostgresDialect) IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolationCode
+}
+
+func (PostgresDialect) CurrentTimestamp() string { return "CURRENT_TIMESTAMP" }
+
+func (PostgresDialect) BindTime(t time.Time) any { return t }
+
+var placeholderPattern = regexp.MustCompile(`\$(\d+)`)
+
+// SQLiteDialect 同一时间只有一个写事务，无需额外加锁
+type SQLiteDialect struct{}
+
+func (SQLiteDialect) Name() string { return SQLiteDialectName }
+
+func (SQLiteDialect) Rebind(query string) string {
+	return placeholderPattern.ReplaceAllString(query, "?$1")
+}
+
+func (SQLiteDialect) LockSQL() string { return "" }
+
+func (SQLiteDialect) SkipLocked() string { return "" }
+
+func (SQLiteDialect) IsUniqueViolation(err error) bool {
+	var sqliteErr interface{ Code() int }
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	code := sqliteErr.Code()
+
+	return code == sqliteConstraintUnique || code == sqliteConstraintPrimaryKey
+}
+
+// CurrentTimestamp CURRENT_TIMESTAMP 只精确到秒，改用毫秒精度
+func (SQLiteDialect) CurrentTimestamp() string { return sqliteCurrentTimestamp }
+
+// BindTime 驱动默认的时间文本宽度不固定且带时区，与写入的时间无法按字符串比较
+func (SQLiteDialect) BindTime(t time.Time) any { return t.UTC().Format(sqliteTimeLayout) }
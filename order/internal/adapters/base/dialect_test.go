@@ -0,0 +1,80 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSQLiteRebind(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "SELECT 1", want: "SELECT 1"},
+		{query: "SELECT * FROM t WHERE a = $1 AND b = $2", want: "SELECT * FROM t WHERE a = ?1 AND b = ?2"},
+		{query: "SELECT * FROM t WHERE a = $10 OR b = $1", want: "SELECT * FROM t WHERE a = ?10 OR b = ?1"},
+		{query: "SELECT * FROM t WHERE b = $2 LIMIT $3", want: "SELECT * FROM t WHERE b = ?2 LIMIT ?3"},
+	}
+
+	for _, tt := range tests {
+		if got := (SQLiteDialect{}).Rebind(tt.query); got != tt.want {
+			t.Errorf("Rebind(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+		if got := (PostgresDialect{}).Rebind(tt.query); got != tt.query {
+			t.Errorf("postgres Rebind(%q) = %q, want unchanged", tt.query, got)
+		}
+	}
+}
+
+func TestSQLiteBindTimeOrdering(t *testing.T) {
+	start := time.Date(2024, 8, 1, 10, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	times := []time.Time{start, start.Add(5 * time.Millisecond), start.Add(500 * time.Millisecond), start.Add(time.Second)}
+
+	for i := 1; i < len(times); i++ {
+		previous := (SQLiteDialect{}).BindTime(times[i-1]).(string)
+		current := (SQLiteDialect{}).BindTime(times[i]).(string)
+		if len(previous) != len(current) || previous >= current {
+			t.Fatalf("expected %q < %q with the same width", previous, current)
+		}
+	}
+
+	if got := (SQLiteDialect{}).BindTime(start); got != "2024-08-01 02:00:00.000" {
+		t.Fatalf("expected UTC time, got %q", got)
+	}
+}
+
+func TestSQLiteUniqueViolation(t *testing.T) {
+	ctx := context.Background()
+	client := newTestSQLiteClient(t)
+
+	err := client.Migrate("items", "CREATE TABLE %s (id text NOT NULL, name text NOT NULL, PRIMARY KEY (id), UNIQUE (name))")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.Exec(ctx, "INSERT INTO items (id, name) VALUES ($1, $2)", "1", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dialect := client.Dialect()
+	if dialect.Name() != SQLiteDialectName {
+		t.Fatalf("expected sqlite dialect, got %s", dialect.Name())
+	}
+
+	err = client.Exec(ctx, "INSERT INTO items (id, name) VALUES ($1, $2)", "1", "b")
+	if !dialect.IsUniqueViolation(err) {
+		t.Fatalf("expected primary key violation, got %v", err)
+	}
+
+	err = client.Exec(ctx, "INSERT INTO items (id, name) VALUES ($1, $2)", "2", "a")
+	if !dialect.IsUniqueViolation(err) {
+		t.Fatalf("expected unique violation, got %v", err)
+	}
+
+	if dialect.IsUniqueViolation(errors.New("other")) || dialect.IsUniqueViolation(nil) {
+		t.Fatal("expected other errors not to be unique violations")
+	}
+}
@@ -6,7 +6,6 @@ type Entity interface {
 	Events() []Event
 	AddEvents(events ...Event)
 	ClearEvents()
-	GetEvent(eventName string) Event
 }
 
 type EntityBase struct {
@@ -2,105 +2,437 @@ package base
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/google/uuid"
+	"strconv"
+	"time"
 )
 
 const (
 	DefaultEventTableName = "events"
-	loadEventsSQL         = "SELECT event_name, event_data FROM %s WHERE entity_name = $1 AND entity_id = $2 AND event_version > $3 ORDER BY event_version ASC"
-	writeEventSQL         = "INSERT INTO %s (entity_name, entity_id, event_version, event_name, event_data, created_at) VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)"
+	DefaultEventPageSize  = 500
+	loadEventsSQL         = "SELECT event_version, event_name, event_data, codec, metadata FROM %s WHERE entity_name = $1 AND entity_id = $2 AND event_version > $3 ORDER BY event_version ASC LIMIT $4"
+	loadEventsAsOfSQL     = "SELECT event_version, event_name, event_data, codec, metadata FROM %s WHERE entity_name = $1 AND entity_id = $2 AND event_version > $3 AND created_at <= $4 ORDER BY event_version ASC LIMIT $5"
+	writeEventSQL         = "INSERT INTO %s (entity_name, entity_id, event_version, event_name, event_data, codec, metadata, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, %s)"
 	CreateEventsTableSQL  = `CREATE TABLE %s (
-    	entity_name    text        NOT NULL,
-    	entity_id      text        NOT NULL,
-		event_version  int         NOT NULL,
-		event_name     text        NOT NULL,
-		event_data     bytea       NOT NULL,
-		created_at     timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
-		PRIMARY KEY (entity_name, entity_id, event_version)
+    	entity_name     text        NOT NULL,
+    	entity_id       text        NOT NULL,
+		event_version   int         NOT NULL,
+		event_name      text        NOT NULL,
+		event_data      bytea       NOT NULL,
+		codec           text        NOT NULL DEFAULT 'json',
+		metadata        jsonb       NOT NULL DEFAULT '{}',
+		global_position bigserial   NOT NULL UNIQUE,
+		transaction_id  xid8        NOT NULL DEFAULT pg_current_xact_id(),
+		created_at      timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (entity_name, entity_id, event_version),
+		UNIQUE (transaction_id, global_position)
+	)`
+	AddEventsMetadataColumnSQL       = "ALTER TABLE %s ADD COLUMN metadata jsonb NOT NULL DEFAULT '{}'"
+	AddEventsCodecColumnSQL          = "ALTER TABLE %s ADD COLUMN codec text NOT NULL DEFAULT 'json'"
+	AddEventsGlobalPositionColumnSQL = "ALTER TABLE %s ADD COLUMN global_position bigserial NOT NULL UNIQUE"
+	AddEventsTransactionIDColumnSQL  = "ALTER TABLE %s ADD COLUMN transaction_id xid8 NOT NULL DEFAULT pg_current_xact_id(), ADD UNIQUE (transaction_id, global_position)"
+	// CreateSQLiteEventsTableSQL SQLite 的自增列只能作为主键，版本唯一性改由唯一约束保证
+	CreateSQLiteEventsTableSQL = `CREATE TABLE %s (
+		global_position integer   PRIMARY KEY AUTOINCREMENT,
+		entity_name     text      NOT NULL,
+		entity_id       text      NOT NULL,
+		event_version   int       NOT NULL,
+		event_name      text      NOT NULL,
+		event_data      blob      NOT NULL,
+		codec           text      NOT NULL DEFAULT 'json',
+		metadata        text      NOT NULL DEFAULT '{}',
+		created_at      timestamp NOT NULL DEFAULT (strftime('%%Y-%%m-%%d %%H:%%M:%%f', 'now')),
+		UNIQUE (entity_name, entity_id, event_version)
 	)`
 )
 
+var eventsTableDDL = DialectSQL{
+	PostgresDialectName: CreateEventsTableSQL,
+	SQLiteDialectName:   CreateSQLiteEventsTableSQL,
+}
+
+// readAllEventsSQL Postgres 只读取 ID 小于所有进行中事务的事务写入的事件，按事务 ID 和全局位置排序，
+// 这部分事件不会再有新增；SQLite 同一时间只有一个写事务，全局位置即提交顺序，$1 不使用
+var readAllEventsSQL = DialectSQL{
+	PostgresDialectName: `SELECT transaction_id::text::bigint, global_position, entity_name, entity_id, event_version, event_name, event_data, codec, metadata FROM %s
+WHERE (transaction_id, global_position) > ($1::text::xid8, $2) AND transaction_id < pg_snapshot_xmin(pg_current_snapshot())
+ORDER BY transaction_id ASC, global_position ASC LIMIT $3`,
+	SQLiteDialectName: "SELECT 0, global_position, entity_name, entity_id, event_version, event_name, event_data, codec, metadata FROM %s WHERE global_position > $2 ORDER BY global_position ASC LIMIT $3",
+}
+
+var ErrUnknownEvent = errors.New("unknown event")
+
 var _ Store = (*EventStore)(nil)
 
+// EventStore 负责事件的序列化、加密和结构升级，读写由 eventTable 完成
 type EventStore struct {
-	tableName string
-	client    Client
+	tableName   string
+	pageSize    int
+	table       eventTable
+	types       *TypeRegistry
+	upcasters   *UpcasterRegistry
+	serializers *serializers
+	keys        *KeyStore
+}
+
+// eventTable 事件的持久化方式，EventEnvelope 中为存储的原始数据
+type eventTable interface {
+	// load asOf 不为零时只返回该时间及之前写入的事件
+	load(ctx context.Context, name, id string, afterVersion, limit int, asOf time.Time) ([]EventEnvelope, error)
+	// append 写入同一聚合根的连续版本，版本已存在时不写入任何事件并返回 ErrConcurrencyConflict
+	append(ctx context.Context, events []EventEnvelope) error
+	readAll(ctx context.Context, after LogPosition, limit int) ([]EventEnvelope, error)
 }
 
 func NewEventStore(client Client, options ...EventStoreOption) *EventStore {
-	store := &EventStore{
-		tableName: DefaultEventTableName,
-		client:    client,
+	store := newEventStore(options...)
+	store.table = &sqlEventTable{tableName: store.tableName, client: client}
+
+	err := client.Migrate(store.tableName, eventsTableDDL.For(client.Dialect()))
+	if err != nil {
+		panic(err)
 	}
 
-	for _, option := range options {
-		option(store)
+	// 以下列在 SQLite 支持之前加入，SQLite 的表建表时已包含
+	err = client.MigrateColumn(store.tableName, "metadata", AddEventsMetadataColumnSQL)
+	if err != nil {
+		panic(err)
+	}
+
+	err = client.MigrateColumn(store.tableName, "global_position", AddEventsGlobalPositionColumnSQL)
+	if err != nil {
+		panic(err)
 	}
 
-	err := client.Migrate(store.tableName, CreateEventsTableSQL)
+	err = client.MigrateColumn(store.tableName, "codec", AddEventsCodecColumnSQL)
 	if err != nil {
 		panic(err)
 	}
 
+	if client.Dialect().Name() == PostgresDialectName {
+		err = client.MigrateColumn(store.tableName, "transaction_id", AddEventsTransactionIDColumnSQL)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	return store
+}
+
+func newEventStore(options ...EventStoreOption) *EventStore {
+	store := &EventStore{
+		tableName:   DefaultEventTableName,
+		pageSize:    DefaultEventPageSize,
+		types:       NewTypeRegistry(),
+		serializers: newSerializers(),
+	}
+
+	for _, option := range options {
+		option(store)
+	}
+
 	return store
 }
 
+// Load 按版本顺序分页读取事件，避免长事件流一次性载入内存
 func (e *EventStore) Load(ctx context.Context, root *AggregateRoot) error {
 	name := root.AggregateName()
 	id := root.AggregateID()
 	version := root.PendingVersion()
+	ring := newKeyRing(e.keys)
+
+	for {
+		limit := e.pageSize
+		if root.bound.version > 0 {
+			limit = min(limit, root.bound.version-version)
+			if limit <= 0 {
+				return nil
+			}
+		}
+
+		envelopes, err := e.table.load(ctx, name, id, version, limit, root.bound.asOf)
+		if err != nil {
+			return err
+		}
 
-	row := e.client.QueryRow(ctx, fmt.Sprintf(loadEventsSQL, e.tableName), name, id, version)
+		for _, envelope := range envelopes {
+			event, err := e.decode(ctx, ring, envelope)
+			if err != nil {
+				return fmt.Errorf("loading %s %s version %d: %w", name, id, envelope.Version, err)
+			}
 
-	var eventName string
-	var data []byte
+			err = root.LoadEvent(event)
+			if err != nil {
+				return err
+			}
+		}
 
-	err := row.Scan(&eventName, &data)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		version += len(envelopes)
+		if len(envelopes) < limit {
 			return nil
 		}
-		return err
 	}
+}
 
-	event := root.GetEvent(eventName)
-	err = json.Unmarshal(data, &event)
+// decode 将存储的原始数据转换到最新结构并还原为事件
+func (e *EventStore) decode(ctx context.Context, ring *keyRing, envelope EventEnvelope) (Event, error) {
+	event, err := e.types.NewEvent(envelope.EventName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = root.LoadEvent(event)
+	metadata := envelope.Metadata
+	schemaVersion := eventSchemaVersion(event)
+	data, err := e.upcasters.UpcastTo(envelope.EventName, metadata.SchemaVersion, schemaVersion, envelope.Codec, envelope.Data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	err = e.serializers.unmarshal(envelope.Codec, data, event)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ring.decrypt(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata.SchemaVersion = schemaVersion
+	setEventMetadata(event, metadata)
+
+	return event, nil
 }
 
-func (e *EventStore) Save(ctx context.Context, root *AggregateRoot) (err error) {
+// Save 写入全部新事件，版本冲突时不写入任何事件并返回 ErrConcurrencyConflict，外层事务仍可继续使用
+func (e *EventStore) Save(ctx context.Context, root *AggregateRoot) error {
 	name := root.AggregateName()
 	id := root.AggregateID()
 	version := root.Version()
 
-	for i, event := range root.Events() {
-		var data []byte
+	metadata := MetadataFromContext(ctx)
+	occurredAt := time.Now().UTC()
+	subject := personalDataSubject(root.Aggregate())
+	ring := newKeyRing(e.keys)
 
-		data, err = json.Marshal(event)
+	envelopes := make([]EventEnvelope, 0, len(root.Events()))
+	for i, event := range root.Events() {
+		value, err := ring.encrypt(ctx, subject, event)
 		if err != nil {
 			return err
 		}
-		err = e.client.Exec(ctx, fmt.Sprintf(writeEventSQL, e.tableName), name, id, version+i+1, event.EventName(), data)
+
+		codec, data, err := e.serializers.marshal(value)
 		if err != nil {
 			return err
 		}
+
+		eventMetadata := metadata
+		eventMetadata.EventID = uuid.New().String()
+		eventMetadata.OccurredAt = occurredAt
+		eventMetadata.SchemaVersion = eventSchemaVersion(event)
+		if eventMetadata.CorrelationID == "" {
+			// 没有上游请求时，同一批事件以第一个事件 ID 关联
+			metadata.CorrelationID = eventMetadata.EventID
+			eventMetadata.CorrelationID = eventMetadata.EventID
+		}
+
+		envelopes = append(envelopes, EventEnvelope{
+			AggregateName: name,
+			AggregateID:   id,
+			Version:       version + i + 1,
+			EventName:     event.EventName(),
+			Data:          data,
+			Codec:         codec,
+			Metadata:      eventMetadata,
+		})
+	}
+
+	err := e.table.append(ctx, envelopes)
+	if err != nil {
+		return err
+	}
+
+	for i, event := range root.Events() {
+		setEventMetadata(event, envelopes[i].Metadata)
 	}
 
 	return nil
 }
 
+// LogPosition 事件在全局日志中的读取位置，先按 TransactionID 再按 Position 排序
+//
+// TransactionID 为写入事件的 Postgres 事务 ID，其他存储为 0；Position 为全局位置
+type LogPosition struct {
+	TransactionID int64
+	Position      int64
+}
+
+// EventEnvelope 全局事件日志中的一条事件，Data 为已转换到最新结构、以 Codec 编码的事件数据，个人数据字段仍为密文
+type EventEnvelope struct {
+	LogPosition
+	AggregateName string
+	AggregateID   string
+	Version       int
+	EventName     string
+	Data          []byte
+	Codec         string
+	Metadata      Metadata
+}
+
+// ReadAll 返回日志中 after 之后的已提交事件，调用方以最后一条的 LogPosition 作为下次读取的起点，从头读取时传零值
+//
+// 写入事件不持有全局锁，Postgres 中全局位置的分配顺序与提交顺序可能不同：只返回早于所有进行中事务的事务写入的事件，
+// 按事务 ID 排序，此后不会再出现排在读取位置之前的事件；长时间运行的事务会推迟其后事件的读取。
+// 回滚的事务会在全局位置中留下空洞，读取时直接跳过
+func (e *EventStore) ReadAll(ctx context.Context, after LogPosition, limit int) ([]EventEnvelope, error) {
+	if limit <= 0 {
+		limit = e.pageSize
+	}
+
+	envelopes, err := e.table.readAll(ctx, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, envelope := range envelopes {
+		envelopes[i].Data, envelopes[i].Metadata.SchemaVersion, err = e.upcasters.Upcast(envelope.EventName, envelope.Metadata.SchemaVersion, envelope.Codec, envelope.Data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return envelopes, nil
+}
+
+// DecodeEvent 将 ReadAll 返回的事件数据还原为已登记的事件类型，解密个人数据并附上元数据
+func (e *EventStore) DecodeEvent(ctx context.Context, envelope EventEnvelope) (Event, error) {
+	return e.decode(ctx, newKeyRing(e.keys), envelope)
+}
+
+type sqlEventTable struct {
+	tableName string
+	client    Client
+}
+
+func (t *sqlEventTable) load(ctx context.Context, name, id string, afterVersion, limit int, asOf time.Time) (envelopes []EventEnvelope, err error) {
+	var rows Rows
+	if asOf.IsZero() {
+		rows, err = t.client.Query(ctx, fmt.Sprintf(loadEventsSQL, t.tableName), name, id, afterVersion, limit)
+	} else {
+		rows, err = t.client.Query(ctx, fmt.Sprintf(loadEventsAsOfSQL, t.tableName), name, id, afterVersion, t.client.Dialect().BindTime(asOf), limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		cErr := rows.Close()
+		if err == nil {
+			err = cErr
+		}
+	}()
+
+	for rows.Next() {
+		envelope := EventEnvelope{AggregateName: name, AggregateID: id}
+		var metadataData []byte
+
+		err = rows.Scan(&envelope.Version, &envelope.EventName, &envelope.Data, &envelope.Codec, &metadataData)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal(metadataData, &envelope.Metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		envelopes = append(envelopes, envelope)
+	}
+
+	return envelopes, rows.Err()
+}
+
+// append 在保存点中写入事件，版本冲突时回滚到保存点
+//
+// 写入前获取该聚合根的事件流锁，只与同一聚合根的写入和归档互斥
+func (t *sqlEventTable) append(ctx context.Context, envelopes []EventEnvelope) error {
+	dialect := t.client.Dialect()
+	first := envelopes[0]
+
+	err := t.client.Transaction(ctx, func(ctx context.Context) error {
+		err := lockStream(ctx, t.client, t.tableName, first.AggregateName, first.AggregateID)
+		if err != nil {
+			return err
+		}
+
+		for _, envelope := range envelopes {
+			metadataData, err := json.Marshal(envelope.Metadata)
+			if err != nil {
+				return err
+			}
+
+			err = t.client.Exec(ctx, fmt.Sprintf(writeEventSQL, t.tableName, dialect.CurrentTimestamp()), envelope.AggregateName, envelope.AggregateID, envelope.Version, envelope.EventName, envelope.Data, envelope.Codec, metadataData)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if dialect.IsUniqueViolation(err) {
+		return fmt.Errorf("%w: %s %s version %d", ErrConcurrencyConflict, first.AggregateName, first.AggregateID, first.Version)
+	}
+
+	return err
+}
+
+func (t *sqlEventTable) readAll(ctx context.Context, after LogPosition, limit int) (envelopes []EventEnvelope, err error) {
+	query := fmt.Sprintf(readAllEventsSQL.For(t.client.Dialect()), t.tableName)
+
+	rows, err := t.client.Query(ctx, query, strconv.FormatInt(after.TransactionID, 10), after.Position, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		cErr := rows.Close()
+		if err == nil {
+			err = cErr
+		}
+	}()
+
+	for rows.Next() {
+		var envelope EventEnvelope
+		var metadataData []byte
+
+		err = rows.Scan(&envelope.TransactionID, &envelope.Position, &envelope.AggregateName, &envelope.AggregateID, &envelope.Version, &envelope.EventName, &envelope.Data, &envelope.Codec, &metadataData)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal(metadataData, &envelope.Metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		envelopes = append(envelopes, envelope)
+	}
+
+	return envelopes, rows.Err()
+}
+
+// lockStream 获取聚合根事件流的事务级锁，直到事务结束才释放
+func lockStream(ctx context.Context, client Client, tableName, name, id string) error {
+	lockSQL := client.Dialect().LockSQL()
+	if lockSQL == "" {
+		return nil
+	}
+
+	return client.Exec(ctx, lockSQL, tableName+":"+name+":"+id)
+}
+
 type EventStoreOption func(*EventStore)
 
 func WithEventTableName(tableName string) EventStoreOption {
@@ -108,3 +440,40 @@ func WithEventTableName(tableName string) EventStoreOption {
 		store.tableName = tableName
 	}
 }
+
+// WithEventTypes 加载事件时按名称创建实例的类型登记表
+func WithEventTypes(types *TypeRegistry) EventStoreOption {
+	return func(store *EventStore) {
+		store.types = types
+	}
+}
+
+// WithEventKeyStore 以数据主体的密钥加密事件中标记的个人数据字段
+func WithEventKeyStore(keys *KeyStore) EventStoreOption {
+	return func(store *EventStore) {
+		store.keys = keys
+	}
+}
+
+// WithEventUpcasters 加载事件前将旧结构的数据转换到最新结构
+func WithEventUpcasters(upcasters *UpcasterRegistry) EventStoreOption {
+	return func(store *EventStore) {
+		store.upcasters = upcasters
+	}
+}
+
+// WithEventSerializer 写入事件时使用的序列化方式，默认 JSON
+func WithEventSerializer(serializer Serializer) EventStoreOption {
+	return func(store *EventStore) {
+		store.serializers.use(serializer)
+	}
+}
+
+// WithEventPageSize 每次查询读取的事件数
+func WithEventPageSize(pageSize int) EventStoreOption {
+	return func(store *EventStore) {
+		if pageSize > 0 {
+			store.pageSize = pageSize
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEventStoreLoadPages(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			events := backend.events(WithEventTypes(testAccountTypes()), WithEventPageSize(2))
+			repository := NewAggregateRootRepository(newTestAccount, events)
+
+			account, err := repository.Save(ctx, testOpenAccount{Owner: "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for amount := 1; amount <= 4; amount++ {
+				_, err = repository.Execute(ctx, account.ID(), testDeposit{Amount: amount})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			table := testRecordLoads(events)
+
+			loaded, err := repository.Load(ctx, account.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if loaded.Version() != 5 || testBalance(t, loaded) != 10 {
+				t.Fatalf("expected version 5 balance 10, got version %d balance %d", loaded.Version(), testBalance(t, loaded))
+			}
+
+			// 5 个事件按每页 2 个读取需要 3 次查询
+			if !reflect.DeepEqual(table.loads, []int{0, 2, 4}) {
+				t.Fatalf("expected pages after versions [0 2 4], got %v", table.loads)
+			}
+		})
+	}
+}
+
+func TestEventStoreLoadExactPage(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			events := backend.events(WithEventTypes(testAccountTypes()), WithEventPageSize(2))
+			repository := NewAggregateRootRepository(newTestAccount, events)
+
+			account, err := repository.Save(ctx, testOpenAccount{Owner: "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = repository.Execute(ctx, account.ID(), testDeposit{Amount: 5})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			table := testRecordLoads(events)
+
+			loaded, err := repository.Load(ctx, account.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if loaded.Version() != 2 || testBalance(t, loaded) != 5 {
+				t.Fatalf("expected version 2 balance 5, got version %d balance %d", loaded.Version(), testBalance(t, loaded))
+			}
+
+			// 最后一页恰好读满时还需一次查询确认没有更多事件
+			if !reflect.DeepEqual(table.loads, []int{0, 2}) {
+				t.Fatalf("expected pages after versions [0 2], got %v", table.loads)
+			}
+		})
+	}
+}
+
+func TestEventStoreLoadUnknownEvent(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			events := backend.events(WithEventTypes(testAccountTypes()))
+			repository := NewAggregateRootRepository(newTestAccount, events)
+
+			account, err := repository.Save(ctx, testOpenAccount{Owner: "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = events.table.append(ctx, []EventEnvelope{{
+				AggregateName: testAccountName,
+				AggregateID:   account.ID(),
+				Version:       2,
+				EventName:     "AccountFrozen",
+				Data:          []byte(`{}`),
+				Codec:         JSONCodec,
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = repository.Load(ctx, account.ID())
+			if !errors.Is(err, ErrUnknownEvent) {
+				t.Fatalf("expected ErrUnknownEvent, got %v", err)
+			}
+		})
+	}
+}
+
+func TestEventStoreReadAll(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			events := backend.events(WithEventTypes(testAccountTypes()))
+			repository := NewAggregateRootRepository(newTestAccount, events)
+
+			alice, err := repository.Save(ctx, testOpenAccount{Owner: "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			bob, err := repository.Save(ctx, testOpenAccount{Owner: "bob"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// 回滚的事件不出现在全局日志中
+			err = backend.client.Transaction(ctx, func(ctx context.Context) error {
+				_, err := repository.Execute(ctx, bob.ID(), testDeposit{Amount: 100})
+				if err != nil {
+					return err
+				}
+
+				return errors.New("rollback")
+			})
+			if err == nil {
+				t.Fatal("expected the transaction to roll back")
+			}
+
+			_, err = repository.Execute(ctx, alice.ID(), testDeposit{Amount: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = repository.Execute(ctx, bob.ID(), testDeposit{Amount: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			type logEntry struct {
+				id      string
+				version int
+			}
+			want := []logEntry{{alice.ID(), 1}, {bob.ID(), 1}, {alice.ID(), 2}, {bob.ID(), 2}}
+
+			// 每次读取 3 个，以最后一条的位置继续读取
+			var got []logEntry
+			var after LogPosition
+			var previous []LogPosition
+			for page := 0; page < 3; page++ {
+				envelopes, err := events.ReadAll(ctx, after, 3)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				for _, envelope := range envelopes {
+					got = append(got, logEntry{envelope.AggregateID, envelope.Version})
+					previous = append(previous, envelope.LogPosition)
+					after = envelope.LogPosition
+				}
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected log %v, got %v", want, got)
+			}
+
+			for i := 1; i < len(previous); i++ {
+				if previous[i].Position <= previous[i-1].Position {
+					t.Fatalf("expected increasing positions, got %v", previous)
+				}
+			}
+
+			envelopes, err := events.ReadAll(ctx, after, 3)
+			if err != nil || len(envelopes) != 0 {
+				t.Fatalf("expected no events after the last position, got %v %v", envelopes, err)
+			}
+
+			envelopes, err = events.ReadAll(ctx, LogPosition{}, 1)
+			if err != nil || len(envelopes) != 1 {
+				t.Fatalf("expected the first event, got %v %v", envelopes, err)
+			}
+
+			event, err := events.DecodeEvent(ctx, envelopes[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if opened, ok := event.(*testAccountOpened); !ok || opened.Owner != "alice" {
+				t.Fatalf("expected decoded AccountOpened for alice, got %#v", event)
+			}
+		})
+	}
+}
+
+func TestLoadAsOf(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			repository := NewAggregateRootRepository(newTestAccount, backend.events(WithEventTypes(testAccountTypes())))
+
+			account, err := repository.Save(ctx, testOpenAccount{Owner: "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// 两次写入在同一秒内，SQLite 只按秒记录时无法区分
+			time.Sleep(20 * time.Millisecond)
+			asOf := time.Now()
+			time.Sleep(20 * time.Millisecond)
+
+			_, err = repository.Execute(ctx, account.ID(), testDeposit{Amount: 10})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := repository.LoadAsOf(ctx, account.ID(), asOf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if loaded.Version() != 1 || testBalance(t, loaded) != 0 {
+				t.Fatalf("expected version 1 balance 0, got version %d balance %d", loaded.Version(), testBalance(t, loaded))
+			}
+
+			loaded, err = repository.LoadAsOf(ctx, account.ID(), time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if loaded.Version() != 2 || testBalance(t, loaded) != 10 {
+				t.Fatalf("expected version 2 balance 10, got version %d balance %d", loaded.Version(), testBalance(t, loaded))
+			}
+
+			_, err = repository.LoadAsOf(ctx, account.ID(), asOf.Add(-time.Hour))
+			if !errors.Is(err, ErrAggregateNotFound) {
+				t.Fatalf("expected ErrAggregateNotFound before creation, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testAccountName = "account"
+
+type testOpenAccount struct {
+	Owner string `pii:"true"`
+}
+
+func (testOpenAccount) CommandName() string { return "OpenAccount" }
+
+type testDeposit struct {
+	Amount int
+}
+
+func (testDeposit) CommandName() string { return "Deposit" }
+
+type testAccountOpened struct {
+	EventBase
+	Owner string `pii:"true"`
+}
+
+func (testAccountOpened) EventName() string { return "AccountOpened" }
+
+type testDeposited struct {
+	EventBase
+	Amount int
+}
+
+func (testDeposited) EventName() string { return "Deposited" }
+
+// SchemaVersion 版本 1 的金额字段名为 Value
+func (testDeposited) SchemaVersion() int { return 2 }
+
+type testAccountSnapshot struct {
+	Owner   string `pii:"true"`
+	Balance int
+}
+
+func (testAccountSnapshot) SnapshotName() string { return "AccountSnapshot" }
+
+type testAccount struct {
+	AggregateBase
+	owner    string
+	balance  int
+	metadata []Metadata
+}
+
+func newTestAccount() Aggregate {
+	return &testAccount{}
+}
+
+func (a *testAccount) EntityName() string { return testAccountName }
+
+func (a *testAccount) PersonalDataSubject() string { return a.ID() }
+
+func (a *testAccount) ProcessCommand(command Command) error {
+	switch c := command.(type) {
+	case testOpenAccount:
+		a.AddEvents(&testAccountOpened{Owner: c.Owner})
+	case testDeposit:
+		a.AddEvents(&testDeposited{Amount: c.Amount})
+	default:
+		return errors.New("unknown command")
+	}
+
+	return nil
+}
+
+func (a *testAccount) ApplyEvent(event Event) error {
+	if metadata, ok := EventMetadata(event); ok {
+		a.metadata = append(a.metadata, metadata)
+	}
+
+	switch e := event.(type) {
+	case *testAccountOpened:
+		a.owner = e.Owner
+	case *testDeposited:
+		a.balance += e.Amount
+	default:
+		return errors.New("unknown event")
+	}
+
+	return nil
+}
+
+func (a *testAccount) ApplySnapshot(snapshot Snapshot) error {
+	s := snapshot.(*testAccountSnapshot)
+	a.owner = s.Owner
+	a.balance = s.Balance
+
+	return nil
+}
+
+func (a *testAccount) ToSnapshot() (Snapshot, error) {
+	return &testAccountSnapshot{Owner: a.owner, Balance: a.balance}, nil
+}
+
+func testAccountTypes() *TypeRegistry {
+	types := NewTypeRegistry()
+	RegisterEvent[testAccountOpened](types)
+	RegisterEvent[testDeposited](types)
+	RegisterSnapshot[testAccountSnapshot](types)
+
+	return types
+}
+
+// testBackend 同一组测试分别在内存存储和 SQLite 上运行
+type testBackend struct {
+	name      string
+	client    Client
+	events    func(options ...EventStoreOption) *EventStore
+	snapshots func(options ...SnapshotStoreOption) StoreMiddleware
+	keys      func(options ...KeyStoreOption) *KeyStore
+}
+
+func testBackends(t *testing.T) []testBackend {
+	t.Helper()
+
+	client := newTestSQLiteClient(t)
+
+	return []testBackend{
+		{
+			name:      "memory",
+			client:    NewMemoryClient(),
+			events:    NewMemoryEventStore,
+			snapshots: NewMemorySnapshotStore,
+			keys:      NewMemoryKeyStore,
+		},
+		{
+			name:   "sqlite",
+			client: client,
+			events: func(options ...EventStoreOption) *EventStore {
+				return NewEventStore(client, options...)
+			},
+			snapshots: func(options ...SnapshotStoreOption) StoreMiddleware {
+				return NewSnapshotStore(client, options...)
+			},
+			keys: func(options ...KeyStoreOption) *KeyStore {
+				return NewKeyStore(client, options...)
+			},
+		},
+	}
+}
+
+// newTestSQLiteClient 每个测试使用独立的 SQLite 文件
+func newTestSQLiteClient(t *testing.T) Client {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	return NewSessionClient(db)
+}
+
+// testEventTable 记录每次按版本读取的起点
+type testEventTable struct {
+	eventTable
+	loads []int
+}
+
+func (t *testEventTable) load(ctx context.Context, name, id string, afterVersion, limit int, asOf time.Time) ([]EventEnvelope, error) {
+	t.loads = append(t.loads, afterVersion)
+
+	return t.eventTable.load(ctx, name, id, afterVersion, limit, asOf)
+}
+
+// testRecordLoads 替换事件存储的 eventTable，返回的 testEventTable 记录此后的读取
+func testRecordLoads(store *EventStore) *testEventTable {
+	table := &testEventTable{eventTable: store.table}
+	store.table = table
+
+	return table
+}
+
+func testBalance(t *testing.T, root *AggregateRoot) int {
+	t.Helper()
+
+	return root.Aggregate().(*testAccount).balance
+}
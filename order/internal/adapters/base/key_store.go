@@ -0,0 +1,163 @@
+package base
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultKeyTableName = "personal_data_keys"
+	dataKeySize         = 32
+	loadKeyBySubjectSQL = "SELECT key_id, data_key FROM %s WHERE subject_id = $1"
+	loadKeyByIDSQL      = "SELECT data_key FROM %s WHERE key_id = $1"
+	createKeySQL        = "INSERT INTO %s (key_id, subject_id, data_key, created_at) VALUES ($1, $2, $3, %s) ON CONFLICT (subject_id) DO NOTHING"
+	forgetKeySQL        = "DELETE FROM %s WHERE subject_id = $1"
+	CreateKeysTableSQL  = `CREATE TABLE %s (
+		key_id     text        NOT NULL,
+		subject_id text        NOT NULL,
+		data_key   bytea       NOT NULL,
+		created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (key_id),
+		UNIQUE (subject_id)
+	)`
+	CreateSQLiteKeysTableSQL = `CREATE TABLE %s (
+		key_id     text      NOT NULL,
+		subject_id text      NOT NULL,
+		data_key   blob      NOT NULL,
+		created_at timestamp NOT NULL DEFAULT (strftime('%%Y-%%m-%%d %%H:%%M:%%f', 'now')),
+		PRIMARY KEY (key_id),
+		UNIQUE (subject_id)
+	)`
+)
+
+var keysTableDDL = DialectSQL{
+	PostgresDialectName: CreateKeysTableSQL,
+	SQLiteDialectName:   CreateSQLiteKeysTableSQL,
+}
+
+// KeyStore 保存每个数据主体（如客户）的数据密钥，删除密钥后以该密钥加密的个人数据无法再解密
+type KeyStore struct {
+	tableName string
+	table     keyTable
+}
+
+// keyTable 密钥的持久化方式
+type keyTable interface {
+	loadSubjectKey(ctx context.Context, subjectID string) (keyID string, key []byte, ok bool, err error)
+	// create 数据主体已有密钥时不写入
+	create(ctx context.Context, keyID, subjectID string, key []byte) error
+	key(ctx context.Context, keyID string) (key []byte, ok bool, err error)
+	forget(ctx context.Context, subjectID string) error
+}
+
+func NewKeyStore(client Client, options ...KeyStoreOption) *KeyStore {
+	store := newKeyStore(options...)
+	store.table = &sqlKeyTable{tableName: store.tableName, client: client}
+
+	err := client.Migrate(store.tableName, keysTableDDL.For(client.Dialect()))
+	if err != nil {
+		panic(err)
+	}
+
+	return store
+}
+
+func newKeyStore(options ...KeyStoreOption) *KeyStore {
+	store := &KeyStore{tableName: DefaultKeyTableName}
+
+	for _, option := range options {
+		option(store)
+	}
+
+	return store
+}
+
+// Forget 销毁数据主体的密钥，已存储的个人数据此后加载为 Redacted
+func (k *KeyStore) Forget(ctx context.Context, subjectID string) error {
+	return k.table.forget(ctx, subjectID)
+}
+
+// subjectKey 返回数据主体的密钥，不存在时创建
+func (k *KeyStore) subjectKey(ctx context.Context, subjectID string) (string, []byte, error) {
+	keyID, key, ok, err := k.table.loadSubjectKey(ctx, subjectID)
+	if err != nil || ok {
+		return keyID, key, err
+	}
+
+	key = make([]byte, dataKeySize)
+	_, err = rand.Read(key)
+	if err != nil {
+		return "", nil, err
+	}
+
+	err = k.table.create(ctx, uuid.New().String(), subjectID, key)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// 并发创建时以先写入的密钥为准
+	keyID, key, ok, err = k.table.loadSubjectKey(ctx, subjectID)
+	if err == nil && !ok {
+		err = fmt.Errorf("key for %s was not created", subjectID)
+	}
+
+	return keyID, key, err
+}
+
+// key 按 ID 返回密钥，密钥已销毁时 ok 为 false
+func (k *KeyStore) key(ctx context.Context, keyID string) ([]byte, bool, error) {
+	return k.table.key(ctx, keyID)
+}
+
+type sqlKeyTable struct {
+	tableName string
+	client    Client
+}
+
+func (t *sqlKeyTable) loadSubjectKey(ctx context.Context, subjectID string) (keyID string, key []byte, ok bool, err error) {
+	row := t.client.QueryRow(ctx, fmt.Sprintf(loadKeyBySubjectSQL, t.tableName), subjectID)
+
+	err = row.Scan(&keyID, &key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil, false, nil
+	}
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	return keyID, key, true, nil
+}
+
+func (t *sqlKeyTable) create(ctx context.Context, keyID, subjectID string, key []byte) error {
+	return t.client.Exec(ctx, fmt.Sprintf(createKeySQL, t.tableName, t.client.Dialect().CurrentTimestamp()), keyID, subjectID, key)
+}
+
+func (t *sqlKeyTable) key(ctx context.Context, keyID string) (key []byte, ok bool, err error) {
+	row := t.client.QueryRow(ctx, fmt.Sprintf(loadKeyByIDSQL, t.tableName), keyID)
+
+	err = row.Scan(&key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return key, true, nil
+}
+
+func (t *sqlKeyTable) forget(ctx context.Context, subjectID string) error {
+	return t.client.Exec(ctx, fmt.Sprintf(forgetKeySQL, t.tableName), subjectID)
+}
+
+type KeyStoreOption func(*KeyStore)
+
+func WithKeyTableName(tableName string) KeyStoreOption {
+	return func(store *KeyStore) {
+		store.tableName = tableName
+	}
+}
@@ -0,0 +1,156 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrUnsupportedSQL = errors.New("in-memory client does not execute SQL")
+
+var _ Client = (*memoryClient)(nil)
+
+type memoryTxKey struct{}
+
+// memoryClient 供内存存储使用的 Client，事务依次串行执行，回滚时撤销内存存储在事务中的写入
+type memoryClient struct {
+	mu sync.Mutex
+}
+
+// NewMemoryClient 不连接数据库，仅与内存存储配合使用，执行 SQL 时返回 ErrUnsupportedSQL
+func NewMemoryClient() Client {
+	return &memoryClient{}
+}
+
+func (c *memoryClient) Exec(context.Context, string, ...any) error {
+	return ErrUnsupportedSQL
+}
+
+func (c *memoryClient) Query(context.Context, string, ...any) (Rows, error) {
+	return nil, ErrUnsupportedSQL
+}
+
+func (c *memoryClient) QueryRow(context.Context, string, ...any) Row {
+	return errRow{err: ErrUnsupportedSQL}
+}
+
+func (c *memoryClient) Migrate(string, string) error {
+	return nil
+}
+
+func (c *memoryClient) MigrateColumn(string, string, string) error {
+	return nil
+}
+
+// Begin 等待其他事务结束后开启事务
+func (c *memoryClient) Begin(ctx context.Context) (context.Context, Tx) {
+	c.mu.Lock()
+
+	tx := &memoryTx{release: c.mu.Unlock}
+
+	return context.WithValue(ctx, memoryTxKey{}, tx), tx
+}
+
+// Transaction ctx 已携带事务时相当于保存点，fn 返回错误只撤销 fn 中的写入
+func (c *memoryClient) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	parent, ok := ctx.Value(memoryTxKey{}).(*memoryTx)
+	if !ok {
+		ctx, tx := c.Begin(ctx)
+		err := fn(ctx)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		return tx.Commit()
+	}
+
+	savepoint := &memoryTx{}
+
+	err := fn(context.WithValue(ctx, memoryTxKey{}, savepoint))
+	if err != nil {
+		_ = savepoint.Rollback()
+		return err
+	}
+
+	parent.undo = append(parent.undo, savepoint.undo...)
+	parent.commit = append(parent.commit, savepoint.commit...)
+
+	return nil
+}
+
+type memoryTx struct {
+	undo    []func()
+	commit  []func()
+	release func()
+	done    bool
+}
+
+func (t *memoryTx) Commit() error {
+	if t.done {
+		return nil
+	}
+
+	for _, commit := range t.commit {
+		commit()
+	}
+	t.finish()
+
+	return nil
+}
+
+func (t *memoryTx) Rollback() error {
+	if t.done {
+		return nil
+	}
+
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		t.undo[i]()
+	}
+	t.finish()
+
+	return nil
+}
+
+func (t *memoryTx) finish() {
+	if t.done {
+		return
+	}
+
+	t.done = true
+	t.undo = nil
+	t.commit = nil
+	if t.release != nil {
+		t.release()
+	}
+}
+
+// Dialect 内存客户端不执行 SQL，返回 Postgres 方言仅为满足接口
+func (c *memoryClient) Dialect() Dialect {
+	return PostgresDialect{}
+}
+
+// OnRollback 登记内存存储写入的撤销操作，ctx 未携带内存事务时写入立即生效，undo 不会被调用
+func OnRollback(ctx context.Context, undo func()) {
+	if tx, ok := ctx.Value(memoryTxKey{}).(*memoryTx); ok && !tx.done {
+		tx.undo = append(tx.undo, undo)
+	}
+}
+
+// OnCommit 登记事务提交后才对其他读取方可见的写入，ctx 未携带内存事务时立即执行
+func OnCommit(ctx context.Context, commit func()) {
+	if tx, ok := ctx.Value(memoryTxKey{}).(*memoryTx); ok && !tx.done {
+		tx.commit = append(tx.commit, commit)
+		return
+	}
+
+	commit()
+}
+
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(...any) error {
+	return r.err
+}
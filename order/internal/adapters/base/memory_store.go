@@ -0,0 +1,240 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// NewMemoryEventStore 数据保存在进程内存中，与 NewMemoryClient 的事务配合时回滚会撤销写入
+func NewMemoryEventStore(options ...EventStoreOption) *EventStore {
+	store := newEventStore(options...)
+	store.table = newMemoryEventTable()
+
+	return store
+}
+
+// NewMemorySnapshotStore 数据保存在进程内存中
+func NewMemorySnapshotStore(options ...SnapshotStoreOption) StoreMiddleware {
+	store := newSnapshotStore(options...)
+	store.table = &memorySnapshotTable{snapshots: make(map[streamKey]snapshotRecord)}
+
+	return store.middleware
+}
+
+// NewMemoryKeyStore 数据保存在进程内存中，进程退出后已加密的数据无法再解密
+func NewMemoryKeyStore(options ...KeyStoreOption) *KeyStore {
+	store := newKeyStore(options...)
+	store.table = &memoryKeyTable{keys: make(map[string][]byte), subjects: make(map[string]string)}
+
+	return store
+}
+
+type streamKey struct {
+	name string
+	id   string
+}
+
+type memoryEventTable struct {
+	mu       sync.Mutex
+	streams  map[streamKey][]EventEnvelope
+	log      []EventEnvelope
+	position int64
+}
+
+func newMemoryEventTable() *memoryEventTable {
+	return &memoryEventTable{streams: make(map[streamKey][]EventEnvelope)}
+}
+
+// load 内存存储以事件的 OccurredAt 作为写入时间
+func (t *memoryEventTable) load(_ context.Context, name, id string, afterVersion, limit int, asOf time.Time) ([]EventEnvelope, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stream := t.streams[streamKey{name: name, id: id}]
+	start := sort.Search(len(stream), func(i int) bool {
+		return stream[i].Version > afterVersion
+	})
+	end := min(start+limit, len(stream))
+	if !asOf.IsZero() {
+		// 同一聚合根的事件按写入顺序追加，写入时间随版本递增
+		end = start + sort.Search(end-start, func(i int) bool {
+			return stream[start+i].Metadata.OccurredAt.After(asOf)
+		})
+	}
+
+	return append([]EventEnvelope(nil), stream[start:end]...), nil
+}
+
+// append 与 Postgres 的唯一约束一致，版本已存在时返回 ErrConcurrencyConflict
+//
+// 事件立即加入聚合根的事件流，事务提交后才加入全局日志，ReadAll 不会读到未提交的事件
+func (t *memoryEventTable) append(ctx context.Context, envelopes []EventEnvelope) error {
+	if len(envelopes) == 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+
+	first := envelopes[0]
+	key := streamKey{name: first.AggregateName, id: first.AggregateID}
+	stream := t.streams[key]
+	previous := len(stream)
+	if previous > 0 && first.Version <= stream[previous-1].Version {
+		t.mu.Unlock()
+		return fmt.Errorf("%w: %s %s version %d", ErrConcurrencyConflict, first.AggregateName, first.AggregateID, first.Version)
+	}
+
+	added := make([]EventEnvelope, 0, len(envelopes))
+	for _, envelope := range envelopes {
+		t.position++
+		envelope.Position = t.position
+
+		stream = append(stream, envelope)
+		added = append(added, envelope)
+	}
+	t.streams[key] = stream
+
+	t.mu.Unlock()
+
+	// 回滚的事件与 Postgres 一样在位置序列中留下空洞
+	OnRollback(ctx, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		t.streams[key] = t.streams[key][:previous]
+	})
+
+	// 内存事务串行执行，提交顺序与位置顺序一致
+	OnCommit(ctx, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		t.log = append(t.log, added...)
+	})
+
+	return nil
+}
+
+func (t *memoryEventTable) readAll(_ context.Context, after LogPosition, limit int) ([]EventEnvelope, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	start := sort.Search(len(t.log), func(i int) bool {
+		return t.log[i].Position > after.Position
+	})
+	end := min(start+limit, len(t.log))
+
+	return append([]EventEnvelope(nil), t.log[start:end]...), nil
+}
+
+type memorySnapshotTable struct {
+	mu        sync.Mutex
+	snapshots map[streamKey]snapshotRecord
+}
+
+func (t *memorySnapshotTable) load(_ context.Context, name, id string) (snapshotRecord, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	record, ok := t.snapshots[streamKey{name: name, id: id}]
+
+	return record, ok, nil
+}
+
+func (t *memorySnapshotTable) save(ctx context.Context, record snapshotRecord) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := streamKey{name: record.aggregateName, id: record.aggregateID}
+	previous, existed := t.snapshots[key]
+	t.snapshots[key] = record
+
+	OnRollback(ctx, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if existed {
+			t.snapshots[key] = previous
+		} else {
+			delete(t.snapshots, key)
+		}
+	})
+
+	return nil
+}
+
+type memoryKeyTable struct {
+	mu       sync.Mutex
+	keys     map[string][]byte // key_id -> data_key
+	subjects map[string]string // subject_id -> key_id
+}
+
+func (t *memoryKeyTable) loadSubjectKey(_ context.Context, subjectID string) (string, []byte, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	keyID, ok := t.subjects[subjectID]
+	if !ok {
+		return "", nil, false, nil
+	}
+
+	return keyID, t.keys[keyID], true, nil
+}
+
+func (t *memoryKeyTable) create(ctx context.Context, keyID, subjectID string, key []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.subjects[subjectID]; ok {
+		return nil
+	}
+
+	t.subjects[subjectID] = keyID
+	t.keys[keyID] = key
+
+	OnRollback(ctx, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		delete(t.subjects, subjectID)
+		delete(t.keys, keyID)
+	})
+
+	return nil
+}
+
+func (t *memoryKeyTable) key(_ context.Context, keyID string) ([]byte, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key, ok := t.keys[keyID]
+
+	return key, ok, nil
+}
+
+func (t *memoryKeyTable) forget(ctx context.Context, subjectID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	keyID, ok := t.subjects[subjectID]
+	if !ok {
+		return nil
+	}
+
+	key := t.keys[keyID]
+	delete(t.subjects, subjectID)
+	delete(t.keys, keyID)
+
+	OnRollback(ctx, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		t.subjects[subjectID] = keyID
+		t.keys[keyID] = key
+	})
+
+	return nil
+}
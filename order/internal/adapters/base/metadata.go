@@ -0,0 +1,78 @@
+package base
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultSchemaVersion 未实现 SchemaVersion 的事件和快照的结构版本
+const DefaultSchemaVersion = 1
+
+// Metadata 事件元数据，与事件数据分开存储
+type Metadata struct {
+	EventID       string    `json:"event_id"`
+	CorrelationID string    `json:"correlation_id,omitempty"`
+	CausationID   string    `json:"causation_id,omitempty"`
+	Actor         string    `json:"actor,omitempty"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	SchemaVersion int       `json:"schema_version"`
+}
+
+// VersionedEvent 事件结构变更时实现，返回当前结构版本，并为旧版本登记 Upcaster
+type VersionedEvent interface {
+	SchemaVersion() int
+}
+
+// EventBase 嵌入到事件中，保存和加载事件时由事件存储填充元数据
+type EventBase struct {
+	metadata Metadata
+}
+
+func (e *EventBase) Metadata() Metadata {
+	return e.metadata
+}
+
+func (e *EventBase) setMetadata(metadata Metadata) {
+	e.metadata = metadata
+}
+
+type metadataEvent interface {
+	setMetadata(metadata Metadata)
+}
+
+// EventMetadata 返回事件的元数据，事件未嵌入 EventBase 时返回 false
+func EventMetadata(event Event) (Metadata, bool) {
+	e, ok := event.(interface{ Metadata() Metadata })
+	if !ok {
+		return Metadata{}, false
+	}
+
+	return e.Metadata(), true
+}
+
+func setEventMetadata(event Event, metadata Metadata) {
+	if e, ok := event.(metadataEvent); ok {
+		e.setMetadata(metadata)
+	}
+}
+
+func eventSchemaVersion(event Event) int {
+	if e, ok := event.(VersionedEvent); ok {
+		return e.SchemaVersion()
+	}
+
+	return DefaultSchemaVersion
+}
+
+type metadataKey struct{}
+
+// ContextWithMetadata ctx 中的关联 ID、因果 ID 和操作者会写入此后保存的事件
+func ContextWithMetadata(ctx context.Context, metadata Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+func MetadataFromContext(ctx context.Context) Metadata {
+	metadata, _ := ctx.Value(metadataKey{}).(Metadata)
+
+	return metadata
+}
@@ -0,0 +1,77 @@
+package base
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEventMetadataRoundTrip(t *testing.T) {
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			repository := NewAggregateRootRepository(newTestAccount, backend.events(WithEventTypes(testAccountTypes())))
+
+			ctx := ContextWithMetadata(context.Background(), Metadata{CorrelationID: "correlation", CausationID: "causation", Actor: "alice"})
+
+			account, err := repository.Save(ctx, testOpenAccount{Owner: "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = repository.Execute(ctx, account.ID(), testDeposit{Amount: 10})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := repository.Load(context.Background(), account.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			metadata := loaded.Aggregate().(*testAccount).metadata
+			if len(metadata) != 2 {
+				t.Fatalf("expected metadata for 2 events, got %d", len(metadata))
+			}
+
+			// Deposited 为结构版本 2
+			schemaVersions := []int{DefaultSchemaVersion, 2}
+			for i, m := range metadata {
+				if m.CorrelationID != "correlation" || m.CausationID != "causation" || m.Actor != "alice" {
+					t.Fatalf("expected metadata from context, got %+v", m)
+				}
+				if m.EventID == "" || m.OccurredAt.IsZero() || m.SchemaVersion != schemaVersions[i] {
+					t.Fatalf("expected event ID, time and schema version to be filled, got %+v", m)
+				}
+			}
+
+			if metadata[0].EventID == metadata[1].EventID {
+				t.Fatalf("expected distinct event IDs, got %s twice", metadata[0].EventID)
+			}
+		})
+	}
+}
+
+func TestEventMetadataDefaultCorrelation(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			repository := NewAggregateRootRepository(newTestAccount, backend.events(WithEventTypes(testAccountTypes())))
+
+			account, err := repository.Save(ctx, testOpenAccount{Owner: "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := repository.Load(ctx, account.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// 没有上游请求时以事件自身的 ID 作为关联 ID
+			m := loaded.Aggregate().(*testAccount).metadata[0]
+			if m.CorrelationID != m.EventID {
+				t.Fatalf("expected correlation ID %s, got %s", m.EventID, m.CorrelationID)
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package base
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const (
+	// Redacted 密钥销毁后个人数据字段加载为该值
+	Redacted = "[redacted]"
+	// personalDataTag 标记个人数据字段，仅支持 string 和 []string，如 `pii:"true"`
+	personalDataTag    = "pii"
+	personalDataPrefix = "pii:"
+)
+
+var ErrInvalidPersonalData = errors.New("invalid personal data")
+
+// PersonalData 聚合实现后，事件和快照中标记的个人数据字段以数据主体的密钥加密存储
+type PersonalData interface {
+	PersonalDataSubject() string
+}
+
+func personalDataSubject(aggregate Aggregate) string {
+	if p, ok := aggregate.(PersonalData); ok {
+		return p.PersonalDataSubject()
+	}
+
+	return ""
+}
+
+// keyRing 单次加载或保存期间缓存用到的密钥，KeyStore 为 nil 时不加密
+type keyRing struct {
+	store    *KeyStore
+	keys     map[string][]byte
+	subjects map[string]string
+}
+
+func newKeyRing(store *KeyStore) *keyRing {
+	return &keyRing{store: store, keys: make(map[string][]byte), subjects: make(map[string]string)}
+}
+
+// encrypt 返回 v 的副本，副本中的个人数据字段已加密，v 本身不被修改
+func (r *keyRing) encrypt(ctx context.Context, subjectID string, v any) (any, error) {
+	if r.store == nil || subjectID == "" {
+		return v, nil
+	}
+
+	var keyID string
+	var aead cipher.AEAD
+
+	value, err := mapPersonalData(reflect.ValueOf(v), func(plaintext string) (string, error) {
+		if plaintext == "" || plaintext == Redacted {
+			return plaintext, nil
+		}
+		if subjectID == Redacted {
+			// 已被遗忘的数据主体不再保存新的个人数据
+			return Redacted, nil
+		}
+
+		if aead == nil {
+			id, key, err := r.subjectKey(ctx, subjectID)
+			if err != nil {
+				return "", err
+			}
+
+			aead, err = newAEAD(key)
+			if err != nil {
+				return "", err
+			}
+			keyID = id
+		}
+
+		nonce := make([]byte, aead.NonceSize())
+		_, err := rand.Read(nonce)
+		if err != nil {
+			return "", err
+		}
+
+		sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(keyID))
+
+		return personalDataPrefix + keyID + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.Interface(), nil
+}
+
+// decrypt 原地解密 v 中的个人数据字段，v 须为指针；密钥已销毁的字段替换为 Redacted，未加密的历史数据保持不变
+func (r *keyRing) decrypt(ctx context.Context, v any) error {
+	if r.store == nil {
+		return nil
+	}
+
+	pointer := reflect.ValueOf(v)
+	value, err := mapPersonalData(pointer, func(ciphertext string) (string, error) {
+		if !strings.HasPrefix(ciphertext, personalDataPrefix) {
+			return ciphertext, nil
+		}
+
+		keyID, encoded, ok := strings.Cut(strings.TrimPrefix(ciphertext, personalDataPrefix), ":")
+		if !ok {
+			return "", fmt.Errorf("%w: malformed value", ErrInvalidPersonalData)
+		}
+
+		key, ok, err := r.key(ctx, keyID)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return Redacted, nil
+		}
+
+		sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidPersonalData, err)
+		}
+
+		aead, err := newAEAD(key)
+		if err != nil {
+			return "", err
+		}
+		if len(sealed) < aead.NonceSize() {
+			return "", fmt.Errorf("%w: ciphertext too short", ErrInvalidPersonalData)
+		}
+
+		plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(keyID))
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidPersonalData, err)
+		}
+
+		return string(plaintext), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if pointer.Kind() == reflect.Pointer && !pointer.IsNil() {
+		pointer.Elem().Set(value.Elem())
+	}
+
+	return nil
+}
+
+func (r *keyRing) subjectKey(ctx context.Context, subjectID string) (string, []byte, error) {
+	if keyID, ok := r.subjects[subjectID]; ok {
+		return keyID, r.keys[keyID], nil
+	}
+
+	keyID, key, err := r.store.subjectKey(ctx, subjectID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	r.subjects[subjectID] = keyID
+	r.keys[keyID] = key
+
+	return keyID, key, nil
+}
+
+func (r *keyRing) key(ctx context.Context, keyID string) ([]byte, bool, error) {
+	if key, ok := r.keys[keyID]; ok {
+		return key, key != nil, nil
+	}
+
+	key, ok, err := r.store.key(ctx, keyID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	r.keys[keyID] = key
+
+	return key, ok, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// personalDataTypes 缓存类型中是否包含个人数据字段
+var personalDataTypes sync.Map
+
+func hasPersonalData(t reflect.Type) bool {
+	if has, ok := personalDataTypes.Load(t); ok {
+		return has.(bool)
+	}
+
+	has := scanPersonalData(t, make(map[reflect.Type]bool))
+	personalDataTypes.Store(t, has)
+
+	return has
+}
+
+func scanPersonalData(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return scanPersonalData(t.Elem(), visiting)
+	case reflect.Struct:
+		if visiting[t] {
+			return false
+		}
+		visiting[t] = true
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Tag.Get(personalDataTag) != "" && isStrings(field.Type) {
+				return true
+			}
+			if scanPersonalData(field.Type, visiting) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isStrings(t reflect.Type) bool {
+	return t.Kind() == reflect.String || (t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String)
+}
+
+// mapPersonalData 返回 v 的副本，副本中标记的个人数据字段替换为 fn 的结果，不含个人数据的部分与 v 共享
+func mapPersonalData(v reflect.Value, fn func(string) (string, error)) (reflect.Value, error) {
+	t := v.Type()
+	if !hasPersonalData(t) {
+		return v, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v, nil
+		}
+
+		elem, err := mapPersonalData(v.Elem(), fn)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		pointer := reflect.New(t.Elem())
+		pointer.Elem().Set(elem)
+
+		return pointer, nil
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && v.IsNil() {
+			return v, nil
+		}
+
+		var out reflect.Value
+		if t.Kind() == reflect.Slice {
+			out = reflect.MakeSlice(t, v.Len(), v.Len())
+		} else {
+			out = reflect.New(t).Elem()
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			elem, err := mapPersonalData(v.Index(i), fn)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out.Index(i).Set(elem)
+		}
+
+		return out, nil
+	case reflect.Struct:
+		out := reflect.New(t).Elem()
+		out.Set(v)
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			var mapped reflect.Value
+			var err error
+			if field.Tag.Get(personalDataTag) != "" && isStrings(field.Type) {
+				mapped, err = mapStrings(out.Field(i), fn)
+			} else {
+				mapped, err = mapPersonalData(out.Field(i), fn)
+			}
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			out.Field(i).Set(mapped)
+		}
+
+		return out, nil
+	}
+
+	return v, nil
+}
+
+func mapStrings(v reflect.Value, fn func(string) (string, error)) (reflect.Value, error) {
+	if v.Kind() == reflect.String {
+		s, err := fn(v.String())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		out := reflect.New(v.Type()).Elem()
+		out.SetString(s)
+
+		return out, nil
+	}
+
+	if v.IsNil() {
+		return v, nil
+	}
+
+	out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	for i := 0; i < v.Len(); i++ {
+		s, err := fn(v.Index(i).String())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.Index(i).SetString(s)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,106 @@
+package base
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPersonalDataForget(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			types := testAccountTypes()
+			keys := backend.keys()
+			events := backend.events(WithEventTypes(types), WithEventKeyStore(keys))
+			snapshots := backend.snapshots(WithSnapshotStoreTypes(types), WithSnapshotStoreKeyStore(keys), WithSnapshotStoreStrategy(NewMaxChangesSnapshotStrategy(2)))
+			repository := NewAggregateRootRepository(newTestAccount, snapshots(events))
+
+			account, err := repository.Save(ctx, testOpenAccount{Owner: "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = repository.Execute(ctx, account.ID(), testDeposit{Amount: 10})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			envelopes, err := events.ReadAll(ctx, LogPosition{}, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(envelopes) != 2 {
+				t.Fatalf("expected 2 events, got %d", len(envelopes))
+			}
+			if strings.Contains(string(envelopes[0].Data), "alice") {
+				t.Fatalf("personal data stored in plaintext: %s", envelopes[0].Data)
+			}
+
+			loaded, err := repository.Load(ctx, account.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if owner := loaded.Aggregate().(*testAccount).owner; owner != "alice" {
+				t.Fatalf("expected owner alice, got %q", owner)
+			}
+
+			err = keys.Forget(ctx, account.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// 版本 2 已有快照，所有者只能来自快照
+			loaded, err = repository.Load(ctx, account.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if owner := loaded.Aggregate().(*testAccount).owner; owner != Redacted {
+				t.Fatalf("expected redacted owner from snapshot, got %q", owner)
+			}
+			if testBalance(t, loaded) != 10 {
+				t.Fatalf("expected balance 10, got %d", testBalance(t, loaded))
+			}
+
+			event, err := events.DecodeEvent(ctx, envelopes[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if owner := event.(*testAccountOpened).Owner; owner != Redacted {
+				t.Fatalf("expected redacted owner from event, got %q", owner)
+			}
+		})
+	}
+}
+
+func TestKeyRingEncryptCopies(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			ring := newKeyRing(backend.keys())
+			event := &testAccountOpened{Owner: "alice"}
+
+			value, err := ring.encrypt(ctx, "subject", event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if event.Owner != "alice" {
+				t.Fatalf("encrypt modified the original value: %q", event.Owner)
+			}
+
+			encrypted := value.(*testAccountOpened)
+			if !strings.HasPrefix(encrypted.Owner, personalDataPrefix) {
+				t.Fatalf("expected encrypted owner, got %q", encrypted.Owner)
+			}
+
+			err = ring.decrypt(ctx, encrypted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if encrypted.Owner != "alice" {
+				t.Fatalf("expected decrypted owner alice, got %q", encrypted.Owner)
+			}
+		})
+	}
+}
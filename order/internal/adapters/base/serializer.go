@@ -0,0 +1,112 @@
+package base
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	JSONCodec        = "json"
+	MessagePackCodec = "msgpack"
+)
+
+var ErrUnknownCodec = errors.New("unknown codec")
+
+// Serializer 事件和快照数据的编解码，Codec 记录在每一行中，加载时按行选择
+type Serializer interface {
+	Codec() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+var DefaultSerializer Serializer = JSONSerializer{}
+
+type JSONSerializer struct{}
+
+func (JSONSerializer) Codec() string { return JSONCodec }
+
+func (JSONSerializer) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONSerializer) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// MessagePackSerializer 沿用 json 标签，无需为事件重复声明字段名
+type MessagePackSerializer struct{}
+
+func (MessagePackSerializer) Codec() string { return MessagePackCodec }
+
+func (MessagePackSerializer) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.SetCustomStructTag("json")
+
+	err := encoder.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (MessagePackSerializer) Unmarshal(data []byte, v any) error {
+	decoder := msgpack.NewDecoder(bytes.NewReader(data))
+	decoder.SetCustomStructTag("json")
+
+	return decoder.Decode(v)
+}
+
+// serializers 写入时使用指定的序列化方式，读取时支持所有已知的编码，迁移期间新旧编码的数据可以共存
+type serializers struct {
+	writer Serializer
+	codecs map[string]Serializer
+}
+
+func newSerializers() *serializers {
+	s := &serializers{
+		writer: DefaultSerializer,
+		codecs: make(map[string]Serializer),
+	}
+
+	for _, serializer := range []Serializer{JSONSerializer{}, MessagePackSerializer{}} {
+		s.codecs[serializer.Codec()] = serializer
+	}
+
+	return s
+}
+
+func (s *serializers) use(serializer Serializer) {
+	s.writer = serializer
+	s.codecs[serializer.Codec()] = serializer
+}
+
+func (s *serializers) marshal(v any) (codec string, data []byte, err error) {
+	data, err = s.writer.Marshal(v)
+
+	return s.writer.Codec(), data, err
+}
+
+func (s *serializers) unmarshal(codec string, data []byte, v any) error {
+	serializer, ok := s.codecs[codec]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownCodec, codec)
+	}
+
+	return serializer.Unmarshal(data, v)
+}
+
+// SerializerByCodec 按名称返回内置的序列化方式
+func SerializerByCodec(codec string) (Serializer, error) {
+	serializer, ok := newSerializers().codecs[codec]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCodec, codec)
+	}
+
+	return serializer, nil
+}
@@ -0,0 +1,151 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMessagePackSerializerRoundTrip(t *testing.T) {
+	serializer := MessagePackSerializer{}
+
+	metadata := Metadata{
+		EventID:       "event",
+		CorrelationID: "correlation",
+		Actor:         "alice",
+		OccurredAt:    time.Date(2024, 7, 1, 8, 30, 0, 0, time.UTC),
+		SchemaVersion: 2,
+	}
+
+	data, err := serializer.Marshal(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Metadata
+	err = serializer.Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.OccurredAt.Equal(metadata.OccurredAt) {
+		t.Fatalf("expected occurred at %v, got %v", metadata.OccurredAt, decoded.OccurredAt)
+	}
+	decoded.OccurredAt = metadata.OccurredAt
+	if decoded != metadata {
+		t.Fatalf("expected %+v, got %+v", metadata, decoded)
+	}
+
+	// 字段名沿用 json 标签
+	var fields map[string]any
+	err = serializer.Unmarshal(data, &fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["event_id"] != "event" {
+		t.Fatalf("expected json tag names, got %v", fields)
+	}
+}
+
+func TestSerializerByCodec(t *testing.T) {
+	for _, codec := range []string{JSONCodec, MessagePackCodec} {
+		serializer, err := SerializerByCodec(codec)
+		if err != nil || serializer.Codec() != codec {
+			t.Fatalf("expected %s serializer, got %v %v", codec, serializer, err)
+		}
+	}
+
+	_, err := SerializerByCodec("protobuf")
+	if !errors.Is(err, ErrUnknownCodec) {
+		t.Fatalf("expected ErrUnknownCodec, got %v", err)
+	}
+}
+
+func TestEventStoreMixedCodecs(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			// 切换编码前后写入的事件和快照在同一事件流中共存
+			events := backend.events(WithEventTypes(testAccountTypes()))
+			msgpackEvents := backend.events(WithEventTypes(testAccountTypes()), WithEventSerializer(MessagePackSerializer{}))
+			msgpackEvents.table = events.table
+
+			snapshots := backend.snapshots(
+				WithSnapshotStoreTypes(testAccountTypes()),
+				WithSnapshotStoreStrategy(NewMaxChangesSnapshotStrategy(3)),
+				WithSnapshotStoreSerializer(MessagePackSerializer{}),
+			)
+			jsonRepository := NewAggregateRootRepository(newTestAccount, events)
+			msgpackRepository := NewAggregateRootRepository(newTestAccount, snapshots(msgpackEvents))
+
+			account, err := jsonRepository.Save(ctx, testOpenAccount{Owner: "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = jsonRepository.Execute(ctx, account.ID(), testDeposit{Amount: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for amount := 2; amount <= 3; amount++ {
+				_, err = msgpackRepository.Execute(ctx, account.ID(), testDeposit{Amount: amount})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			envelopes, err := events.ReadAll(ctx, LogPosition{}, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			codecs := []string{JSONCodec, JSONCodec, MessagePackCodec, MessagePackCodec}
+			if len(envelopes) != len(codecs) {
+				t.Fatalf("expected %d events, got %d", len(codecs), len(envelopes))
+			}
+			for i, envelope := range envelopes {
+				if envelope.Codec != codecs[i] {
+					t.Fatalf("event %d: expected codec %s, got %s", i+1, codecs[i], envelope.Codec)
+				}
+			}
+
+			table := testRecordLoads(msgpackEvents)
+
+			for name, repository := range map[string]*AggregateRootRepository{"json": jsonRepository, "msgpack": msgpackRepository} {
+				loaded, err := repository.Load(ctx, account.ID())
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+
+				if loaded.Version() != 4 || testBalance(t, loaded) != 6 {
+					t.Fatalf("%s: expected version 4 balance 6, got version %d balance %d", name, loaded.Version(), testBalance(t, loaded))
+				}
+			}
+
+			// msgpack 仓储从版本 3 的快照开始加载
+			if len(table.loads) != 1 || table.loads[0] != 3 {
+				t.Fatalf("expected events after the version 3 snapshot, got loads %v", table.loads)
+			}
+
+			err = events.table.append(ctx, []EventEnvelope{{
+				AggregateName: testAccountName,
+				AggregateID:   account.ID(),
+				Version:       5,
+				EventName:     "Deposited",
+				Data:          []byte{0x0a, 0x01},
+				Codec:         "protobuf",
+				Metadata:      Metadata{SchemaVersion: 2},
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = jsonRepository.Load(ctx, account.ID())
+			if !errors.Is(err, ErrUnknownCodec) {
+				t.Fatalf("expected ErrUnknownCodec, got %v", err)
+			}
+		})
+	}
+}
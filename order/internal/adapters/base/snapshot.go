@@ -3,3 +3,16 @@ package base
 type Snapshot interface {
 	SnapshotName() string
 }
+
+// VersionedSnapshot 快照结构变更时实现，返回当前结构版本
+type VersionedSnapshot interface {
+	SchemaVersion() int
+}
+
+func snapshotSchemaVersion(snapshot Snapshot) int {
+	if s, ok := snapshot.(VersionedSnapshot); ok {
+		return s.SchemaVersion()
+	}
+
+	return DefaultSchemaVersion
+}
@@ -3,87 +3,171 @@ package base
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
 )
 
 const (
 	DefaultSnapshotTableName = "snapshots"
-	loadSnapshotSQL          = "SELECT snapshot_name, snapshot_data, snapshot_version FROM %s WHERE entity_name = $1 AND entity_id = $2 LIMIT 1"
-	saveSnapshotSQL          = `INSERT INTO %s (entity_name, entity_id, snapshot_name, snapshot_data, snapshot_version, modified_at) 
-VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP) 
+	loadSnapshotSQL          = "SELECT snapshot_name, snapshot_data, codec, snapshot_version, schema_version FROM %s WHERE entity_name = $1 AND entity_id = $2 LIMIT 1"
+	saveSnapshotSQL          = `INSERT INTO %s (entity_name, entity_id, snapshot_name, snapshot_data, codec, snapshot_version, schema_version, modified_at) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, %s) 
 ON CONFLICT (entity_name, entity_id) DO
-UPDATE SET snapshot_name = EXCLUDED.snapshot_name, snapshot_data = EXCLUDED.snapshot_data, snapshot_version = EXCLUDED.snapshot_version, modified_at = EXCLUDED.modified_at`
+UPDATE SET snapshot_name = EXCLUDED.snapshot_name, snapshot_data = EXCLUDED.snapshot_data, codec = EXCLUDED.codec, snapshot_version = EXCLUDED.snapshot_version, schema_version = EXCLUDED.schema_version, modified_at = EXCLUDED.modified_at`
 	CreateSnapshotsTableSQL = `CREATE TABLE %s (
 		entity_name      text        NOT NULL,
 		entity_id        text        NOT NULL,
 		snapshot_name    text        NOT NULL,
 		snapshot_data    bytea       NOT NULL,
+		codec            text        NOT NULL DEFAULT 'json',
 		snapshot_version int         NOT NULL,
+		schema_version   int         NOT NULL DEFAULT 1,
 		modified_at      timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
 		PRIMARY KEY (entity_name, entity_id)
 	)`
+	AddSnapshotsSchemaVersionColumnSQL = "ALTER TABLE %s ADD COLUMN schema_version int NOT NULL DEFAULT 1"
+	AddSnapshotsCodecColumnSQL         = "ALTER TABLE %s ADD COLUMN codec text NOT NULL DEFAULT 'json'"
+	CreateSQLiteSnapshotsTableSQL      = `CREATE TABLE %s (
+		entity_name      text      NOT NULL,
+		entity_id        text      NOT NULL,
+		snapshot_name    text      NOT NULL,
+		snapshot_data    blob      NOT NULL,
+		codec            text      NOT NULL DEFAULT 'json',
+		snapshot_version int       NOT NULL,
+		schema_version   int       NOT NULL DEFAULT 1,
+		modified_at      timestamp NOT NULL DEFAULT (strftime('%%Y-%%m-%%d %%H:%%M:%%f', 'now')),
+		PRIMARY KEY (entity_name, entity_id)
+	)`
 )
 
+var snapshotsTableDDL = DialectSQL{
+	PostgresDialectName: CreateSnapshotsTableSQL,
+	SQLiteDialectName:   CreateSQLiteSnapshotsTableSQL,
+}
+
 type SnapshotStore struct {
-	tableName string
-	client    Client
-	strategy  SnapshotStrategy
-	next      Store
+	tableName   string
+	table       snapshotTable
+	strategy    SnapshotStrategy
+	types       *TypeRegistry
+	upcasters   *UpcasterRegistry
+	serializers *serializers
+	keys        *KeyStore
+	next        Store
+}
+
+// snapshotRecord 存储的快照原始数据，每个聚合根只保留最新的一份
+type snapshotRecord struct {
+	aggregateName string
+	aggregateID   string
+	snapshotName  string
+	data          []byte
+	codec         string
+	version       int
+	schemaVersion int
+}
+
+// snapshotTable 快照的持久化方式
+type snapshotTable interface {
+	load(ctx context.Context, name, id string) (record snapshotRecord, ok bool, err error)
+	save(ctx context.Context, record snapshotRecord) error
 }
 
 func NewSnapshotStore(client Client, options ...SnapshotStoreOption) StoreMiddleware {
-	store := &SnapshotStore{
-		tableName: DefaultSnapshotTableName,
-		client:    client,
-		strategy:  DefaultSnapshotStrategies,
+	store := newSnapshotStore(options...)
+	store.table = &sqlSnapshotTable{tableName: store.tableName, client: client}
+
+	err := client.Migrate(store.tableName, snapshotsTableDDL.For(client.Dialect()))
+	if err != nil {
+		panic(err)
 	}
 
-	for _, option := range options {
-		option(store)
+	// 以下列在 SQLite 支持之前加入，SQLite 的表建表时已包含
+	err = client.MigrateColumn(store.tableName, "schema_version", AddSnapshotsSchemaVersionColumnSQL)
+	if err != nil {
+		panic(err)
 	}
 
-	err := client.Migrate(store.tableName, CreateSnapshotsTableSQL)
+	err = client.MigrateColumn(store.tableName, "codec", AddSnapshotsCodecColumnSQL)
 	if err != nil {
 		panic(err)
 	}
 
-	return func(next Store) Store {
-		store.next = next
-		return store
+	return store.middleware
+}
+
+func newSnapshotStore(options ...SnapshotStoreOption) *SnapshotStore {
+	store := &SnapshotStore{
+		tableName:   DefaultSnapshotTableName,
+		strategy:    DefaultSnapshotStrategies,
+		types:       NewTypeRegistry(),
+		serializers: newSerializers(),
 	}
+
+	for _, option := range options {
+		option(store)
+	}
+
+	return store
+}
+
+func (s *SnapshotStore) middleware(next Store) Store {
+	s.next = next
+	return s
 }
 
 func (s *SnapshotStore) Load(ctx context.Context, root *AggregateRoot) error {
 	name := root.AggregateName()
 	id := root.AggregateID()
 
-	row := s.client.QueryRow(ctx, fmt.Sprintf(loadSnapshotSQL, s.tableName), name, id)
+	// 快照不记录写入时间，按时间加载时直接从事件重建
+	if !root.bound.asOf.IsZero() {
+		return s.next.Load(ctx, root)
+	}
+
+	record, ok, err := s.table.load(ctx, name, id)
+	if err != nil {
+		return err
+	}
+	if !ok || (root.bound.version > 0 && record.version > root.bound.version) {
+		return s.next.Load(ctx, root)
+	}
+
+	snapshot, err := s.types.NewSnapshot(record.snapshotName)
+	if errors.Is(err, ErrUnknownSnapshot) {
+		// 快照类型已变更或未登记，从事件重建
+		return s.next.Load(ctx, root)
+	}
+	if err != nil {
+		return fmt.Errorf("loading %s %s snapshot version %d: %w", name, id, record.version, err)
+	}
 
-	var data []byte
-	var version int
+	data, err := s.upcasters.UpcastTo(record.snapshotName, record.schemaVersion, snapshotSchemaVersion(snapshot), record.codec, record.data)
+	if errors.Is(err, ErrMissingUpcaster) {
+		// 快照无法转换到最新结构时丢弃，从事件重建
+		return s.next.Load(ctx, root)
+	}
+	if err != nil {
+		return err
+	}
 
-	err := row.Scan(&data, &version)
+	err = s.serializers.unmarshal(record.codec, data, snapshot)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return s.next.Load(ctx, root)
-		}
 		return err
 	}
 
-	snapshot := root.GetSnapshotType()
-	err = json.Unmarshal(data, &snapshot)
+	err = newKeyRing(s.keys).decrypt(ctx, snapshot)
 	if err != nil {
 		return err
 	}
 
-	err = root.LoadSnapshot(snapshot, version)
+	err = root.LoadSnapshot(snapshot, record.version)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// 继续加载快照之后的事件
+	return s.next.Load(ctx, root)
 }
 
 func (s *SnapshotStore) Save(ctx context.Context, root *AggregateRoot) error {
@@ -101,20 +185,50 @@ func (s *SnapshotStore) Save(ctx context.Context, root *AggregateRoot) error {
 		return err
 	}
 
-	name := root.AggregateName()
-	id := root.AggregateID()
-	version := root.PendingVersion()
-	data, err := json.Marshal(snapshot)
+	value, err := newKeyRing(s.keys).encrypt(ctx, personalDataSubject(root.Aggregate()), snapshot)
 	if err != nil {
 		return err
 	}
 
-	err = s.client.Exec(ctx, fmt.Sprintf(saveSnapshotSQL, s.tableName), name, id, snapshot.SnapshotName(), data, version)
+	codec, data, err := s.serializers.marshal(value)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return s.table.save(ctx, snapshotRecord{
+		aggregateName: root.AggregateName(),
+		aggregateID:   root.AggregateID(),
+		snapshotName:  snapshot.SnapshotName(),
+		data:          data,
+		codec:         codec,
+		version:       root.PendingVersion(),
+		schemaVersion: snapshotSchemaVersion(snapshot),
+	})
+}
+
+type sqlSnapshotTable struct {
+	tableName string
+	client    Client
+}
+
+func (t *sqlSnapshotTable) load(ctx context.Context, name, id string) (snapshotRecord, bool, error) {
+	row := t.client.QueryRow(ctx, fmt.Sprintf(loadSnapshotSQL, t.tableName), name, id)
+
+	record := snapshotRecord{aggregateName: name, aggregateID: id}
+
+	err := row.Scan(&record.snapshotName, &record.data, &record.codec, &record.version, &record.schemaVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		return snapshotRecord{}, false, nil
+	}
+	if err != nil {
+		return snapshotRecord{}, false, err
+	}
+
+	return record, true, nil
+}
+
+func (t *sqlSnapshotTable) save(ctx context.Context, record snapshotRecord) error {
+	return t.client.Exec(ctx, fmt.Sprintf(saveSnapshotSQL, t.tableName, t.client.Dialect().CurrentTimestamp()), record.aggregateName, record.aggregateID, record.snapshotName, record.data, record.codec, record.version, record.schemaVersion)
 }
 
 type SnapshotStoreOption func(*SnapshotStore)
@@ -130,3 +244,31 @@ func WithSnapshotStoreStrategy(strategy SnapshotStrategy) SnapshotStoreOption {
 		store.strategy = strategy
 	}
 }
+
+// WithSnapshotStoreTypes 加载快照时按名称创建实例的类型登记表
+func WithSnapshotStoreTypes(types *TypeRegistry) SnapshotStoreOption {
+	return func(store *SnapshotStore) {
+		store.types = types
+	}
+}
+
+// WithSnapshotStoreKeyStore 以数据主体的密钥加密快照中标记的个人数据字段
+func WithSnapshotStoreKeyStore(keys *KeyStore) SnapshotStoreOption {
+	return func(store *SnapshotStore) {
+		store.keys = keys
+	}
+}
+
+// WithSnapshotStoreUpcasters 加载快照前将旧结构的数据转换到最新结构
+func WithSnapshotStoreUpcasters(upcasters *UpcasterRegistry) SnapshotStoreOption {
+	return func(store *SnapshotStore) {
+		store.upcasters = upcasters
+	}
+}
+
+// WithSnapshotStoreSerializer 写入快照时使用的序列化方式，默认 JSON
+func WithSnapshotStoreSerializer(serializer Serializer) SnapshotStoreOption {
+	return func(store *SnapshotStore) {
+		store.serializers.use(serializer)
+	}
+}
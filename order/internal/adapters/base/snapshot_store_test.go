@@ -0,0 +1,147 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func testSnapshotRepository(backend testBackend, types *TypeRegistry) (*AggregateRootRepository, *testEventTable) {
+	events := backend.events(WithEventTypes(testAccountTypes()))
+	snapshots := backend.snapshots(WithSnapshotStoreTypes(types), WithSnapshotStoreStrategy(NewMaxChangesSnapshotStrategy(2)))
+
+	return NewAggregateRootRepository(newTestAccount, snapshots(events)), testRecordLoads(events)
+}
+
+func TestSnapshotStoreLoadEventsAfterSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			repository, table := testSnapshotRepository(backend, testAccountTypes())
+
+			account, err := repository.Save(ctx, testOpenAccount{Owner: "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for amount := 1; amount <= 2; amount++ {
+				_, err = repository.Execute(ctx, account.ID(), testDeposit{Amount: amount})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			table.loads = nil
+
+			loaded, err := repository.Load(ctx, account.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if loaded.Version() != 3 || testBalance(t, loaded) != 3 || loaded.Aggregate().(*testAccount).owner != "alice" {
+				t.Fatalf("expected alice version 3 balance 3, got version %d balance %d", loaded.Version(), testBalance(t, loaded))
+			}
+
+			// 版本 2 时保存了快照，只需读取之后的事件
+			if !reflect.DeepEqual(table.loads, []int{2}) {
+				t.Fatalf("expected events after version 2, got loads %v", table.loads)
+			}
+		})
+	}
+}
+
+func TestSnapshotStoreLoadRenamedSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			// 快照类型未登记，相当于快照已改名
+			types := NewTypeRegistry()
+			RegisterEvent[testAccountOpened](types)
+			RegisterEvent[testDeposited](types)
+			repository, table := testSnapshotRepository(backend, types)
+
+			account, err := repository.Save(ctx, testOpenAccount{Owner: "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = repository.Execute(ctx, account.ID(), testDeposit{Amount: 5})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			table.loads = nil
+
+			loaded, err := repository.Load(ctx, account.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if loaded.Version() != 2 || testBalance(t, loaded) != 5 {
+				t.Fatalf("expected version 2 balance 5, got version %d balance %d", loaded.Version(), testBalance(t, loaded))
+			}
+
+			if !reflect.DeepEqual(table.loads, []int{0}) {
+				t.Fatalf("expected rebuilding from the first event, got loads %v", table.loads)
+			}
+		})
+	}
+}
+
+func TestSnapshotStoreLoadAtVersion(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			repository, table := testSnapshotRepository(backend, testAccountTypes())
+
+			account, err := repository.Save(ctx, testOpenAccount{Owner: "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for amount := 1; amount <= 2; amount++ {
+				_, err = repository.Execute(ctx, account.ID(), testDeposit{Amount: amount})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			tests := []struct {
+				version     int
+				wantVersion int
+				wantBalance int
+				wantLoads   []int
+			}{
+				// 快照晚于要求的版本时从事件重建
+				{version: 1, wantVersion: 1, wantBalance: 0, wantLoads: []int{0}},
+				{version: 2, wantVersion: 2, wantBalance: 1, wantLoads: nil},
+				{version: 10, wantVersion: 3, wantBalance: 3, wantLoads: []int{2}},
+			}
+
+			for _, tt := range tests {
+				table.loads = nil
+
+				loaded, err := repository.LoadAtVersion(ctx, account.ID(), tt.version)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if loaded.Version() != tt.wantVersion || testBalance(t, loaded) != tt.wantBalance {
+					t.Fatalf("version %d: expected version %d balance %d, got version %d balance %d", tt.version, tt.wantVersion, tt.wantBalance, loaded.Version(), testBalance(t, loaded))
+				}
+				if !reflect.DeepEqual(table.loads, tt.wantLoads) {
+					t.Fatalf("version %d: expected loads %v, got %v", tt.version, tt.wantLoads, table.loads)
+				}
+			}
+
+			_, err = repository.LoadAtVersion(ctx, account.ID(), 0)
+			if !errors.Is(err, ErrAggregateNotFound) {
+				t.Fatalf("expected ErrAggregateNotFound, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrUnknownSnapshot = errors.New("unknown snapshot")
+
+// TypeRegistry 按名称登记事件和快照类型，存储加载时据此创建实例
+type TypeRegistry struct {
+	events    map[string]func() Event
+	snapshots map[string]func() Snapshot
+}
+
+func NewTypeRegistry() *TypeRegistry {
+	return &TypeRegistry{
+		events:    make(map[string]func() Event),
+		snapshots: make(map[string]func() Snapshot),
+	}
+}
+
+// RegisterEvent 以 EventName 登记事件类型，加载时创建 *T，重复登记同名事件会 panic
+func RegisterEvent[T any, P interface {
+	*T
+	Event
+}](r *TypeRegistry) {
+	name := P(new(T)).EventName()
+	if _, ok := r.events[name]; ok {
+		panic(fmt.Sprintf("duplicate event type: %s", name))
+	}
+
+	r.events[name] = func() Event { return P(new(T)) }
+}
+
+// RegisterSnapshot 以 SnapshotName 登记快照类型，加载时创建 *T，重复登记同名快照会 panic
+func RegisterSnapshot[T any, P interface {
+	*T
+	Snapshot
+}](r *TypeRegistry) {
+	name := P(new(T)).SnapshotName()
+	if _, ok := r.snapshots[name]; ok {
+		panic(fmt.Sprintf("duplicate snapshot type: %s", name))
+	}
+
+	r.snapshots[name] = func() Snapshot { return P(new(T)) }
+}
+
+func (r *TypeRegistry) NewEvent(name string) (Event, error) {
+	newEvent, ok := r.events[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, name)
+	}
+
+	return newEvent(), nil
+}
+
+func (r *TypeRegistry) NewSnapshot(name string) (Snapshot, error) {
+	newSnapshot, ok := r.snapshots[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSnapshot, name)
+	}
+
+	return newSnapshot(), nil
+}
+
+// EventNames 已登记的事件名称，按字母排序
+func (r *TypeRegistry) EventNames() []string {
+	return sortedNames(r.events)
+}
+
+// SnapshotNames 已登记的快照名称，按字母排序
+func (r *TypeRegistry) SnapshotNames() []string {
+	return sortedNames(r.snapshots)
+}
+
+func sortedNames[V any](types map[string]V) []string {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
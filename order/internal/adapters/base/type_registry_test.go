@@ -0,0 +1,73 @@
+package base
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTypeRegistry(t *testing.T) {
+	types := testAccountTypes()
+
+	event, err := types.NewEvent("Deposited")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := event.(*testDeposited); !ok {
+		t.Fatalf("expected *testDeposited, got %T", event)
+	}
+
+	// 每次创建新的实例
+	other, _ := types.NewEvent("Deposited")
+	if event == other {
+		t.Fatal("expected a new event instance")
+	}
+
+	snapshot, err := types.NewSnapshot("AccountSnapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := snapshot.(*testAccountSnapshot); !ok {
+		t.Fatalf("expected *testAccountSnapshot, got %T", snapshot)
+	}
+
+	if names := types.EventNames(); !reflect.DeepEqual(names, []string{"AccountOpened", "Deposited"}) {
+		t.Fatalf("expected sorted event names, got %v", names)
+	}
+	if names := types.SnapshotNames(); !reflect.DeepEqual(names, []string{"AccountSnapshot"}) {
+		t.Fatalf("expected snapshot names, got %v", names)
+	}
+}
+
+func TestTypeRegistryUnknownName(t *testing.T) {
+	types := testAccountTypes()
+
+	_, err := types.NewEvent("AccountFrozen")
+	if !errors.Is(err, ErrUnknownEvent) {
+		t.Fatalf("expected ErrUnknownEvent, got %v", err)
+	}
+
+	_, err = types.NewSnapshot("LegacyAccountSnapshot")
+	if !errors.Is(err, ErrUnknownSnapshot) {
+		t.Fatalf("expected ErrUnknownSnapshot, got %v", err)
+	}
+}
+
+func TestTypeRegistryDuplicate(t *testing.T) {
+	tests := map[string]func(types *TypeRegistry){
+		"event":    func(types *TypeRegistry) { RegisterEvent[testDeposited](types) },
+		"snapshot": func(types *TypeRegistry) { RegisterSnapshot[testAccountSnapshot](types) },
+	}
+
+	for name, register := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected panic on duplicate %s type", name)
+				}
+			}()
+
+			register(testAccountTypes())
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrMissingUpcaster = errors.New("missing upcaster")
+
+// Upcaster 将存储的数据从某个结构版本转换到下一个版本，codec 为该行数据的编码
+type Upcaster func(codec string, data []byte) ([]byte, error)
+
+type upcasterKey struct {
+	name    string
+	version int
+}
+
+// UpcasterRegistry 按事件或快照名称及结构版本登记 Upcaster，加载时逐级转换到最新结构，不改写已存储的数据
+type UpcasterRegistry struct {
+	upcasters map[upcasterKey]Upcaster
+}
+
+func NewUpcasterRegistry() *UpcasterRegistry {
+	return &UpcasterRegistry{upcasters: make(map[upcasterKey]Upcaster)}
+}
+
+// Register 登记从 fromVersion 转换到 fromVersion+1 的 Upcaster
+func (r *UpcasterRegistry) Register(name string, fromVersion int, upcaster Upcaster) {
+	key := upcasterKey{name: name, version: fromVersion}
+	if _, ok := r.upcasters[key]; ok {
+		panic(fmt.Sprintf("duplicate upcaster: %s version %d", name, fromVersion))
+	}
+
+	r.upcasters[key] = upcaster
+}
+
+// Upcast 依次应用已登记的 Upcaster，返回转换后的数据和结构版本
+func (r *UpcasterRegistry) Upcast(name string, version int, codec string, data []byte) ([]byte, int, error) {
+	// 元数据中没有结构版本的历史数据按初始版本处理
+	if version < DefaultSchemaVersion {
+		version = DefaultSchemaVersion
+	}
+
+	if r == nil {
+		return data, version, nil
+	}
+
+	for {
+		upcaster, ok := r.upcasters[upcasterKey{name: name, version: version}]
+		if !ok {
+			return data, version, nil
+		}
+
+		var err error
+		data, err = upcaster(codec, data)
+		if err != nil {
+			return nil, version, fmt.Errorf("upcasting %s from version %d: %w", name, version, err)
+		}
+
+		version++
+	}
+}
+
+// UpcastTo 转换到指定版本，缺少中间步骤时返回 ErrMissingUpcaster
+func (r *UpcasterRegistry) UpcastTo(name string, version int, target int, codec string, data []byte) ([]byte, error) {
+	data, version, err := r.Upcast(name, version, codec, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if version != target {
+		return nil, fmt.Errorf("%w: %s from version %d to %d", ErrMissingUpcaster, name, version, target)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,148 @@
+package base
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testRenameField 返回将 JSON 字段 from 改名为 to 的 Upcaster
+func testRenameField(from, to string) Upcaster {
+	return func(codec string, data []byte) ([]byte, error) {
+		var fields map[string]any
+		err := json.Unmarshal(data, &fields)
+		if err != nil {
+			return nil, err
+		}
+
+		fields[to] = fields[from]
+		delete(fields, from)
+
+		return json.Marshal(fields)
+	}
+}
+
+func TestUpcasterRegistryChain(t *testing.T) {
+	upcasters := NewUpcasterRegistry()
+	upcasters.Register("Deposited", 1, testRenameField("Value", "Cents"))
+	upcasters.Register("Deposited", 2, testRenameField("Cents", "Amount"))
+
+	tests := []struct {
+		name        string
+		version     int
+		data        string
+		wantVersion int
+	}{
+		{name: "from first version", version: 1, data: `{"Value":5}`, wantVersion: 3},
+		{name: "from middle version", version: 2, data: `{"Cents":5}`, wantVersion: 3},
+		{name: "without schema version", version: 0, data: `{"Value":5}`, wantVersion: 3},
+		{name: "already latest", version: 3, data: `{"Amount":5}`, wantVersion: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, version, err := upcasters.Upcast("Deposited", tt.version, JSONCodec, []byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if version != tt.wantVersion || !bytes.Equal(data, []byte(`{"Amount":5}`)) {
+				t.Fatalf("expected version %d %s, got version %d %s", tt.wantVersion, `{"Amount":5}`, version, data)
+			}
+		})
+	}
+}
+
+func TestUpcasterRegistryMissingUpcaster(t *testing.T) {
+	upcasters := NewUpcasterRegistry()
+	upcasters.Register("Deposited", 1, testRenameField("Value", "Cents"))
+
+	// 缺少 2 到 3 的转换
+	_, err := upcasters.UpcastTo("Deposited", 1, 3, JSONCodec, []byte(`{"Value":5}`))
+	if !errors.Is(err, ErrMissingUpcaster) {
+		t.Fatalf("expected ErrMissingUpcaster, got %v", err)
+	}
+
+	var registry *UpcasterRegistry
+	_, err = registry.UpcastTo("Deposited", 1, 2, JSONCodec, []byte(`{"Value":5}`))
+	if !errors.Is(err, ErrMissingUpcaster) {
+		t.Fatalf("expected ErrMissingUpcaster without registry, got %v", err)
+	}
+}
+
+func TestUpcasterRegistryDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic on duplicate upcaster")
+		}
+	}()
+
+	upcasters := NewUpcasterRegistry()
+	upcasters.Register("Deposited", 1, testRenameField("Value", "Amount"))
+	upcasters.Register("Deposited", 1, testRenameField("Value", "Amount"))
+}
+
+func TestEventStoreLoadUpcastsEvents(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range testBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			upcasters := NewUpcasterRegistry()
+			upcasters.Register("Deposited", 1, testRenameField("Value", "Amount"))
+			events := backend.events(WithEventTypes(testAccountTypes()), WithEventUpcasters(upcasters))
+			repository := NewAggregateRootRepository(newTestAccount, events)
+
+			account, err := repository.Save(ctx, testOpenAccount{Owner: "alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// 版本 1 的历史事件
+			err = events.table.append(ctx, []EventEnvelope{{
+				AggregateName: testAccountName,
+				AggregateID:   account.ID(),
+				Version:       2,
+				EventName:     "Deposited",
+				Data:          []byte(`{"Value":7}`),
+				Codec:         JSONCodec,
+				Metadata:      Metadata{SchemaVersion: 1},
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = repository.Execute(ctx, account.ID(), testDeposit{Amount: 3})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := repository.Load(ctx, account.ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if loaded.Version() != 3 || testBalance(t, loaded) != 10 {
+				t.Fatalf("expected version 3 balance 10, got version %d balance %d", loaded.Version(), testBalance(t, loaded))
+			}
+
+			// 已存储的数据不被改写
+			envelopes, err := events.table.load(ctx, testAccountName, account.ID(), 1, 1, time.Time{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(envelopes) != 1 || string(envelopes[0].Data) != `{"Value":7}` {
+				t.Fatalf("expected stored data to stay unchanged, got %v", envelopes)
+			}
+
+			withoutUpcasters := backend.events(WithEventTypes(testAccountTypes()))
+			withoutUpcasters.table = events.table
+			_, err = NewAggregateRootRepository(newTestAccount, withoutUpcasters).Load(ctx, account.ID())
+			if !errors.Is(err, ErrMissingUpcaster) {
+				t.Fatalf("expected ErrMissingUpcaster without upcasters, got %v", err)
+			}
+		})
+	}
+}
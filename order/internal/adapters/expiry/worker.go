@@ -0,0 +1,112 @@
+package expiry
+
+import (
+	"context"
+	"log"
+	"order/internal/adapters/base"
+	"order/internal/ports"
+	"time"
+)
+
+const (
+	// actor 超时拒绝事件的操作者
+	actor = "order-expiry"
+	// retryBackoff 处理失败的订单再次处理前的等待时间
+	retryBackoff = time.Minute
+)
+
+// Worker 定期将超时未确认的订单置为已拒绝，到期记录持久化在数据库中，重启后继续处理
+type Worker struct {
+	app       ports.Application
+	deadlines ports.OrderDeadlineRepository
+	client    base.Client
+	interval  time.Duration
+}
+
+func NewWorker(app ports.Application, deadlines ports.OrderDeadlineRepository, client base.Client, interval time.Duration) *Worker {
+	return &Worker{
+		app:       app,
+		deadlines: deadlines,
+		client:    client,
+		interval:  interval,
+	}
+}
+
+func (w *Worker) Run(ctx context.Context) error {
+	ctx = base.ContextWithMetadata(ctx, base.Metadata{Actor: actor})
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.expireDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) expireDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		orderID, expired, err := w.expireNext(ctx)
+		if err != nil && orderID == "" {
+			log.Printf("error while expiring orders: %v", err.Error())
+			return
+		}
+
+		if err != nil {
+			// 推迟失败的记录，避免一直处理失败的订单阻塞其后到期的订单
+			log.Printf("error while expiring order %s: %v", orderID, err.Error())
+
+			err = w.postpone(ctx, orderID)
+			if err != nil {
+				log.Printf("error while postponing order %s expiry: %v", orderID, err.Error())
+				return
+			}
+
+			continue
+		}
+
+		if !expired {
+			return
+		}
+	}
+}
+
+// expireNext 每个订单在独立事务中处理，处理失败时返回该订单 ID
+func (w *Worker) expireNext(ctx context.Context) (orderID string, expired bool, err error) {
+	ctx, tx := w.client.Begin(ctx)
+
+	defer func() {
+		if err != nil || !expired {
+			tx.Rollback()
+			return
+		}
+
+		if tErr := tx.Commit(); tErr != nil {
+			log.Printf("error while committing the order expiry transaction: %v", tErr)
+		}
+	}()
+
+	now := time.Now()
+
+	orderID, ok, err := w.deadlines.NextDue(ctx, now)
+	if err != nil || !ok {
+		return "", false, err
+	}
+
+	err = w.app.ExpireOrder(ctx, orderID, now)
+	if err != nil {
+		return orderID, false, err
+	}
+
+	return orderID, true, nil
+}
+
+// postpone 将到期记录推迟 retryBackoff 后再处理
+func (w *Worker) postpone(ctx context.Context, orderID string) error {
+	return w.deadlines.Schedule(ctx, orderID, time.Now().Add(retryBackoff))
+}
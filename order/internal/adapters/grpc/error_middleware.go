@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"order/internal/adapters/base"
+	"order/internal/application/core/domain"
+	"strings"
+)
+
+// requestFields 将领域字段路径转换为请求字段路径
+var requestFields = strings.NewReplacer(
+	"customer_id", "user_id",
+	".product_id", ".product_code",
+	".number", ".quantity",
+	"shipment.", "",
+)
+
+func ErrorUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		resp, err = handler(ctx, req)
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+
+		return resp, nil
+	}
+}
+
+func toStatusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return badRequest(validationErr)
+	case errors.Is(err, base.ErrAggregateNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, base.ErrConcurrencyConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, domain.ErrOrderInvalidState):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrOrderInvalidRevision),
+		errors.Is(err, domain.ErrOrderInvalidCoupon),
+		errors.Is(err, domain.ErrOrderInvalidShipment),
+		errors.Is(err, domain.ErrOrderInvalidReturn),
+		errors.Is(err, domain.ErrTaxRateNotFound),
+		errors.Is(err, domain.ErrMoneyInvalidCurrency),
+		errors.Is(err, domain.ErrMoneyCurrencyMismatch):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return err
+	}
+}
+
+func badRequest(err *domain.ValidationError) error {
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(err.Violations))
+	for _, violation := range err.Violations {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       requestFields.Replace(violation.Field),
+			Description: violation.Description,
+		})
+	}
+
+	st, dErr := status.New(codes.InvalidArgument, err.Error()).WithDetails(&errdetails.BadRequest{
+		FieldViolations: violations,
+	})
+	if dErr != nil {
+		log.Printf("error while attaching bad request details: %v", dErr.Error())
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"order/internal/adapters/base"
+	"order/internal/application/core/domain"
+	"testing"
+)
+
+func TestRequestFields(t *testing.T) {
+	tests := map[string]string{
+		"customer_id":                   "user_id",
+		"order_items":                   "order_items",
+		"order_items[0].product_id":     "order_items[0].product_code",
+		"order_items[12].number":        "order_items[12].quantity",
+		"order_items[3].price":          "order_items[3].price",
+		"order_items[3].price.currency": "order_items[3].price.currency",
+		"item_deltas[1].product_id":     "item_deltas[1].product_code",
+	}
+
+	for field, want := range tests {
+		if got := requestFields.Replace(field); got != want {
+			t.Errorf("%s: expected %q, got %q", field, want, got)
+		}
+	}
+}
+
+func TestErrorInterceptorBadRequest(t *testing.T) {
+	command := domain.CreateOrder{
+		CustomerID: " ",
+		OrderItems: []domain.CreateOrderItem{
+			{ProductId: "p1", Price: domain.NewMoney(100, "CNY"), Number: 1},
+			{ProductId: "p1", Price: domain.NewMoney(100, "CNY"), Number: 0},
+		},
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("create order: %w", command.Validate())
+	}
+
+	_, err := ErrorUnaryInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+
+	var badRequest *errdetails.BadRequest
+	for _, detail := range st.Details() {
+		if d, ok := detail.(*errdetails.BadRequest); ok {
+			badRequest = d
+		}
+	}
+	if badRequest == nil {
+		t.Fatalf("expected BadRequest details, got %v", st.Details())
+	}
+
+	want := []string{"user_id", "order_items[1].product_code", "order_items[1].quantity"}
+	violations := badRequest.GetFieldViolations()
+	if len(violations) != len(want) {
+		t.Fatalf("expected %d violations, got %v", len(want), violations)
+	}
+	for i, field := range want {
+		if violations[i].GetField() != field {
+			t.Errorf("violation %d: expected field %q, got %q", i, field, violations[i].GetField())
+		}
+		if violations[i].GetDescription() == "" {
+			t.Errorf("violation %d: expected a description", i)
+		}
+	}
+}
+
+func TestToStatusError(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{fmt.Errorf("order not found: %w", base.ErrAggregateNotFound), codes.NotFound},
+		{fmt.Errorf("%w: approve", domain.ErrOrderInvalidState), codes.FailedPrecondition},
+		{domain.ErrMoneyCurrencyMismatch, codes.InvalidArgument},
+		{status.Error(codes.Unavailable, "down"), codes.Unavailable},
+	}
+
+	for _, tt := range tests {
+		if got := status.Code(toStatusError(tt.err)); got != tt.code {
+			t.Errorf("%v: expected %s, got %s", tt.err, tt.code, got)
+		}
+	}
+}
@@ -2,11 +2,14 @@ package grpc
 
 import (
 	"context"
-	"github.com/jinleibill/microservices-proto/golang/order"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"order/internal/adapters/base"
+	"order/internal/application/core/domain"
 	"order/internal/application/core/dto"
 	"order/internal/ports"
+	"order/proto/order"
+	"time"
 )
 
 type Adapter struct {
@@ -26,16 +29,24 @@ func (a *Adapter) Mount(registrar grpc.ServiceRegistrar) {
 func (a *Adapter) Create(ctx context.Context, request *order.CreateOrderRequest) (*order.CreateOrderResponse, error) {
 	var orderItems []dto.CreateOrderItemDTO
 	for _, orderItem := range request.OrderItems {
+		price, err := toMoney(orderItem)
+		if err != nil {
+			return nil, err
+		}
+
 		orderItems = append(orderItems, dto.CreateOrderItemDTO{
 			ProductId: orderItem.ProductCode,
-			Price:     orderItem.UnitPrice,
+			Price:     price,
 			Number:    orderItem.Quantity,
+			TaxClass:  orderItem.TaxClass,
 		})
 	}
 
 	result, err := a.app.CreateOrder(ctx, dto.CreateOrderDTO{
-		CustomerID: request.UserId,
-		OrderItems: orderItems,
+		CustomerID:      request.UserId,
+		OrderItems:      orderItems,
+		DeliveryAddress: toAddress(request.DeliveryAddress),
+		TTL:             request.Ttl.AsDuration(),
 	})
 	if err != nil {
 		return nil, err
@@ -45,7 +56,13 @@ func (a *Adapter) Create(ctx context.Context, request *order.CreateOrderRequest)
 }
 
 func (a *Adapter) Get(ctx context.Context, request *order.GetOrderRequest) (*order.GetOrderResponse, error) {
-	o, err := a.app.GetOrder(ctx, request.OrderId)
+	var o *domain.Order
+	var err error
+	if request.AsOf != nil {
+		o, err = a.app.GetOrderAsOf(ctx, request.OrderId, request.AsOf.AsTime())
+	} else {
+		o, err = a.app.GetOrder(ctx, request.OrderId)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -53,14 +70,426 @@ func (a *Adapter) Get(ctx context.Context, request *order.GetOrderRequest) (*ord
 	orderItems := make([]*order.OrderItem, 0, len(o.OrderItems))
 	for _, orderItem := range o.OrderItems {
 		orderItems = append(orderItems, &order.OrderItem{
-			ProductCode: orderItem.ProductId,
-			UnitPrice:   orderItem.Price,
-			Quantity:    orderItem.Number,
+			ProductCode:         orderItem.ProductId,
+			UnitPrice:           float32(orderItem.Price.Float64()),
+			Quantity:            orderItem.Number,
+			Price:               fromMoney(orderItem.Price),
+			TaxClass:            orderItem.TaxClass,
+			ShippedQuantity:     orderItem.Shipped,
+			OutstandingQuantity: orderItem.Outstanding(),
 		})
 	}
 
+	var pendingItemDeltas []*order.OrderItemDelta
+	if o.Revision != nil {
+		pendingItemDeltas = toOrderItemDeltas(o.Revision.ItemDeltas)
+	}
+
 	return &order.GetOrderResponse{
-		UserId:     o.CustomerID,
-		OrderItems: orderItems,
+		UserId:            o.CustomerID,
+		OrderItems:        orderItems,
+		Status:            o.State.String(),
+		OrderTotal:        fromMoney(o.OrderTotal),
+		PendingItemDeltas: pendingItemDeltas,
+		DeliveryAddress:   fromAddress(o.DeliveryAddress),
+		Coupons:           fromCoupons(o.Coupons),
+		Discounts:         fromDiscounts(o.Discounts),
+		Tax:               fromTaxSummary(o.Tax),
+		ExpiresAt:         fromTime(o.ExpiresAt),
+		Shipments:         fromShipments(o.Shipments),
+		Returns:           fromReturns(o.Returns),
 	}, nil
 }
+
+func (a *Adapter) Approve(ctx context.Context, request *order.ApproveOrderRequest) (*order.ApproveOrderResponse, error) {
+	err := a.app.ApproveOrder(ctx, request.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.ApproveOrderResponse{}, nil
+}
+
+func (a *Adapter) Reject(ctx context.Context, request *order.RejectOrderRequest) (*order.RejectOrderResponse, error) {
+	err := a.app.RejectOrder(ctx, request.OrderId, request.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.RejectOrderResponse{}, nil
+}
+
+func (a *Adapter) Cancel(ctx context.Context, request *order.CancelOrderRequest) (*order.CancelOrderResponse, error) {
+	err := a.app.BeginCancelOrder(ctx, request.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.CancelOrderResponse{}, nil
+}
+
+func (a *Adapter) ConfirmCancel(ctx context.Context, request *order.ConfirmCancelOrderRequest) (*order.ConfirmCancelOrderResponse, error) {
+	err := a.app.ConfirmCancelOrder(ctx, request.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.ConfirmCancelOrderResponse{}, nil
+}
+
+func (a *Adapter) UndoCancel(ctx context.Context, request *order.UndoCancelOrderRequest) (*order.UndoCancelOrderResponse, error) {
+	err := a.app.UndoCancelOrder(ctx, request.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.UndoCancelOrderResponse{}, nil
+}
+
+func (a *Adapter) Revise(ctx context.Context, request *order.ReviseOrderRequest) (*order.ReviseOrderResponse, error) {
+	var itemDeltas []dto.OrderItemDeltaDTO
+	for _, itemDelta := range request.ItemDeltas {
+		itemDeltas = append(itemDeltas, dto.OrderItemDeltaDTO{
+			ProductId: itemDelta.ProductCode,
+			Delta:     itemDelta.QuantityDelta,
+		})
+	}
+
+	result, err := a.app.ReviseOrder(ctx, request.OrderId, dto.ReviseOrderDTO{
+		ItemDeltas: itemDeltas,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.ReviseOrderResponse{RevisedTotal: fromMoney(result.Revision.RevisedTotal)}, nil
+}
+
+func (a *Adapter) ConfirmRevision(ctx context.Context, request *order.ConfirmRevisionRequest) (*order.ConfirmRevisionResponse, error) {
+	err := a.app.ConfirmRevision(ctx, request.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.ConfirmRevisionResponse{}, nil
+}
+
+func (a *Adapter) RejectRevision(ctx context.Context, request *order.RejectRevisionRequest) (*order.RejectRevisionResponse, error) {
+	err := a.app.RejectRevision(ctx, request.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.RejectRevisionResponse{}, nil
+}
+
+func (a *Adapter) ChangeDeliveryAddress(ctx context.Context, request *order.ChangeDeliveryAddressRequest) (*order.ChangeDeliveryAddressResponse, error) {
+	err := a.app.ChangeDeliveryAddress(ctx, request.OrderId, toAddress(request.DeliveryAddress))
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.ChangeDeliveryAddressResponse{}, nil
+}
+
+func (a *Adapter) ApplyCoupon(ctx context.Context, request *order.ApplyCouponRequest) (*order.ApplyCouponResponse, error) {
+	result, err := a.app.ApplyCoupon(ctx, request.OrderId, toCoupon(request.Coupon))
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.ApplyCouponResponse{
+		OrderTotal: fromMoney(result.OrderTotal),
+		Discounts:  fromDiscounts(result.Discounts),
+	}, nil
+}
+
+func (a *Adapter) RemoveCoupon(ctx context.Context, request *order.RemoveCouponRequest) (*order.RemoveCouponResponse, error) {
+	result, err := a.app.RemoveCoupon(ctx, request.OrderId, request.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.RemoveCouponResponse{
+		OrderTotal: fromMoney(result.OrderTotal),
+		Discounts:  fromDiscounts(result.Discounts),
+	}, nil
+}
+
+func (a *Adapter) RecordShipment(ctx context.Context, request *order.RecordShipmentRequest) (*order.RecordShipmentResponse, error) {
+	var items []dto.ShipmentItemDTO
+	for _, item := range request.Items {
+		items = append(items, dto.ShipmentItemDTO{
+			ProductId: item.ProductCode,
+			Number:    item.Quantity,
+		})
+	}
+
+	result, err := a.app.RecordShipment(ctx, request.OrderId, dto.RecordShipmentDTO{
+		Carrier:        request.Carrier,
+		TrackingNumber: request.TrackingNumber,
+		Items:          items,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.RecordShipmentResponse{Status: result.State.String()}, nil
+}
+
+func (a *Adapter) RequestReturn(ctx context.Context, request *order.RequestReturnRequest) (*order.RequestReturnResponse, error) {
+	returnID, err := a.app.RequestReturn(ctx, request.OrderId, toReturnDTO(request.ReasonCode, request.Items))
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.RequestReturnResponse{ReturnId: returnID}, nil
+}
+
+func (a *Adapter) ReceiveReturn(ctx context.Context, request *order.ReceiveReturnRequest) (*order.ReceiveReturnResponse, error) {
+	err := a.app.ReceiveReturn(ctx, request.OrderId, request.ReturnId, toReturnDTO(request.ReasonCode, request.Items))
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.ReceiveReturnResponse{}, nil
+}
+
+func (a *Adapter) IssueRefund(ctx context.Context, request *order.IssueRefundRequest) (*order.IssueRefundResponse, error) {
+	result, err := a.app.IssueRefund(ctx, request.OrderId, request.ReturnId, toReturnDTO(request.ReasonCode, request.Items))
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.IssueRefundResponse{Return: fromReturn(result)}, nil
+}
+
+func (a *Adapter) ForgetCustomer(ctx context.Context, request *order.ForgetCustomerRequest) (*order.ForgetCustomerResponse, error) {
+	err := a.app.ForgetCustomer(ctx, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.ForgetCustomerResponse{}, nil
+}
+
+func (a *Adapter) RestoreOrder(ctx context.Context, request *order.RestoreOrderRequest) (*order.RestoreOrderResponse, error) {
+	err := a.app.RestoreOrder(ctx, request.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order.RestoreOrderResponse{}, nil
+}
+
+func toOrderItemDeltas(itemDeltas []domain.OrderItemDelta) []*order.OrderItemDelta {
+	result := make([]*order.OrderItemDelta, 0, len(itemDeltas))
+	for _, itemDelta := range itemDeltas {
+		result = append(result, &order.OrderItemDelta{
+			ProductCode:   itemDelta.ProductId,
+			QuantityDelta: itemDelta.Delta,
+		})
+	}
+
+	return result
+}
+
+func toMoney(orderItem *order.OrderItem) (domain.Money, error) {
+	if orderItem.Price != nil {
+		return domain.NewMoney(orderItem.Price.Amount, orderItem.Price.Currency), nil
+	}
+
+	return domain.NewMoneyFromFloat(float64(orderItem.UnitPrice), domain.DefaultCurrency, domain.RoundHalfUp)
+}
+
+func fromMoney(money domain.Money) *order.Money {
+	return &order.Money{
+		Amount:   money.Amount,
+		Currency: money.Currency,
+	}
+}
+
+func toAddress(address *order.Address) domain.Address {
+	if address == nil {
+		return domain.Address{}
+	}
+
+	return domain.Address{
+		Recipient:  address.Recipient,
+		Phone:      address.Phone,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Lines:      address.Lines,
+	}
+}
+
+func fromAddress(address domain.Address) *order.Address {
+	if address.IsZero() {
+		return nil
+	}
+
+	return &order.Address{
+		Recipient:  address.Recipient,
+		Phone:      address.Phone,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Lines:      address.Lines,
+	}
+}
+
+func toCoupon(coupon *order.Coupon) domain.Coupon {
+	if coupon == nil {
+		return domain.Coupon{}
+	}
+
+	result := domain.Coupon{
+		Code:        coupon.Code,
+		BasisPoints: coupon.BasisPoints,
+		ProductId:   coupon.ProductCode,
+	}
+
+	switch coupon.Kind {
+	case order.CouponKind_PERCENTAGE:
+		result.Kind = domain.PercentageCoupon
+	case order.CouponKind_FIXED_AMOUNT:
+		result.Kind = domain.FixedAmountCoupon
+	}
+
+	if coupon.Amount != nil {
+		result.Amount = domain.NewMoney(coupon.Amount.Amount, coupon.Amount.Currency)
+	}
+
+	return result
+}
+
+func fromCoupons(coupons []domain.Coupon) []*order.Coupon {
+	result := make([]*order.Coupon, 0, len(coupons))
+	for _, coupon := range coupons {
+		c := &order.Coupon{
+			Code:        coupon.Code,
+			BasisPoints: coupon.BasisPoints,
+			ProductCode: coupon.ProductId,
+		}
+
+		switch coupon.Kind {
+		case domain.PercentageCoupon:
+			c.Kind = order.CouponKind_PERCENTAGE
+		case domain.FixedAmountCoupon:
+			c.Kind = order.CouponKind_FIXED_AMOUNT
+			c.Amount = fromMoney(coupon.Amount)
+		}
+
+		result = append(result, c)
+	}
+
+	return result
+}
+
+func fromDiscounts(discounts []domain.Discount) []*order.Discount {
+	result := make([]*order.Discount, 0, len(discounts))
+	for _, discount := range discounts {
+		result = append(result, &order.Discount{
+			Source:      discount.Source,
+			ProductCode: discount.ProductId,
+			Amount:      fromMoney(discount.Amount),
+		})
+	}
+
+	return result
+}
+
+func fromTaxSummary(summary domain.TaxSummary) *order.TaxSummary {
+	lines := make([]*order.LineTax, 0, len(summary.Lines))
+	for _, line := range summary.Lines {
+		lines = append(lines, &order.LineTax{
+			ProductCode: line.ProductId,
+			TaxClass:    line.TaxClass,
+			Rate:        line.Rate,
+			Net:         fromMoney(line.Net),
+			Tax:         fromMoney(line.Tax),
+			Gross:       fromMoney(line.Gross),
+		})
+	}
+
+	return &order.TaxSummary{
+		Lines: lines,
+		Net:   fromMoney(summary.Net),
+		Tax:   fromMoney(summary.Tax),
+		Gross: fromMoney(summary.Gross),
+	}
+}
+
+func fromShipments(shipments []domain.Shipment) []*order.Shipment {
+	result := make([]*order.Shipment, 0, len(shipments))
+	for _, shipment := range shipments {
+		items := make([]*order.ShipmentItem, 0, len(shipment.Items))
+		for _, item := range shipment.Items {
+			items = append(items, &order.ShipmentItem{
+				ProductCode: item.ProductId,
+				Quantity:    item.Number,
+			})
+		}
+
+		result = append(result, &order.Shipment{
+			Carrier:        shipment.Carrier,
+			TrackingNumber: shipment.TrackingNumber,
+			Items:          items,
+			ShippedAt:      fromTime(&shipment.ShippedAt),
+		})
+	}
+
+	return result
+}
+
+func toReturnDTO(reasonCode string, items []*order.ReturnItem) dto.ReturnDTO {
+	result := dto.ReturnDTO{ReasonCode: reasonCode}
+	for _, item := range items {
+		result.Items = append(result.Items, dto.ReturnItemDTO{
+			ProductId: item.ProductCode,
+			Number:    item.Quantity,
+		})
+	}
+
+	return result
+}
+
+func fromReturns(returns []domain.Return) []*order.Return {
+	result := make([]*order.Return, 0, len(returns))
+	for _, r := range returns {
+		result = append(result, fromReturn(r))
+	}
+
+	return result
+}
+
+func fromReturn(r domain.Return) *order.Return {
+	lines := make([]*order.ReturnLine, 0, len(r.Items))
+	for _, item := range r.Items {
+		line := &order.ReturnLine{
+			ProductCode:       item.ProductId,
+			RequestedQuantity: item.Requested,
+			ReceivedQuantity:  item.Received,
+			RefundedQuantity:  item.Refunded,
+		}
+		if item.Refunded > 0 {
+			line.RefundedAmount = fromMoney(item.RefundedAmount)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return &order.Return{
+		ReturnId:   r.ID,
+		ReasonCode: r.ReasonCode,
+		Status:     r.Status.String(),
+		Lines:      lines,
+	}
+}
+
+func fromTime(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
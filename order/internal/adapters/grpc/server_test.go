@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"order/internal/adapters/base"
+	orderAdapter "order/internal/adapters/order"
+	"order/internal/adapters/tax"
+	"order/internal/application/core/application"
+	"order/internal/application/core/domain"
+	"order/proto/order"
+	"testing"
+)
+
+// testOrderClient 启动基于内存存储的服务，请求经过与线上相同的拦截器
+func testOrderClient(t *testing.T) order.OrderClient {
+	t.Helper()
+
+	types := base.NewTypeRegistry()
+	domain.RegisterOrderTypes(types)
+
+	client := base.NewMemoryClient()
+	keys := base.NewMemoryKeyStore()
+	events := base.NewMemoryEventStore(base.WithEventTypes(types), base.WithEventKeyStore(keys))
+	store := base.NewMemorySnapshotStore(base.WithSnapshotStoreTypes(types), base.WithSnapshotStoreKeyStore(keys))(events)
+
+	taxAdapter, err := tax.NewAdapter("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := application.NewApplication(orderAdapter.NewAdapter(store), taxAdapter, orderAdapter.NewMemoryDeadlineAdapter(), keys)
+
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		ErrorUnaryInterceptor(),
+		SessionUnaryInterceptor(client),
+	))
+	NewAdapter(app, client).Mount(server)
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return order.NewOrderClient(conn)
+}
+
+func TestOrderLifecycle(t *testing.T) {
+	ctx := context.Background()
+	client := testOrderClient(t)
+
+	created, err := client.Create(ctx, &order.CreateOrderRequest{
+		UserId: "customer-1",
+		OrderItems: []*order.OrderItem{
+			{ProductCode: "p1", Quantity: 2, Price: &order.Money{Amount: 1000, Currency: "CNY"}},
+		},
+		DeliveryAddress: &order.Address{
+			Recipient: "Alice",
+			Phone:     "13800000000",
+			Region:    "CN-31",
+			Lines:     []string{"1 Century Avenue"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Approve(ctx, &order.ApproveOrderRequest{OrderId: created.OrderId})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.ForgetCustomer(ctx, &order.ForgetCustomerRequest{UserId: "customer-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 客户被遗忘后订单仍可继续处理
+	shipped, err := client.RecordShipment(ctx, &order.RecordShipmentRequest{
+		OrderId:        created.OrderId,
+		Carrier:        "sf",
+		TrackingNumber: "SF1",
+		Items:          []*order.ShipmentItem{{ProductCode: "p1", Quantity: 2}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shipped.Status != domain.Shipped.String() {
+		t.Fatalf("expected status %s, got %s", domain.Shipped, shipped.Status)
+	}
+
+	got, err := client.Get(ctx, &order.GetOrderRequest{OrderId: created.OrderId})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Status != domain.Shipped.String() || len(got.Shipments) != 1 || got.OrderItems[0].ShippedQuantity != 2 {
+		t.Fatalf("expected a shipped order with one shipment, got %v", got)
+	}
+	if got.UserId != base.Redacted || got.DeliveryAddress.Recipient != base.Redacted || got.DeliveryAddress.Region != "CN-31" {
+		t.Fatalf("expected redacted personal data, got user %q address %v", got.UserId, got.DeliveryAddress)
+	}
+	if got.OrderTotal.Amount != 2000 {
+		t.Fatalf("expected order total 2000, got %d", got.OrderTotal.Amount)
+	}
+
+	// 失败的请求经错误拦截器转换为状态码，事务回滚
+	_, err = client.Approve(ctx, &order.ApproveOrderRequest{OrderId: created.OrderId})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition, got %v", err)
+	}
+
+	_, err = client.Get(ctx, &order.GetOrderRequest{OrderId: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+}
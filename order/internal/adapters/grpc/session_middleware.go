@@ -2,33 +2,40 @@ package grpc
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"log"
 	"order/internal/adapters/base"
 )
 
+const (
+	correlationIDHeader = "x-correlation-id"
+	causationIDHeader   = "x-causation-id"
+	requestIDHeader     = "x-request-id"
+	actorHeader         = "x-actor"
+)
+
 func SessionUnaryInterceptor(client base.Client) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
-		tx := client.GetTx().WithContext(ctx)
+		ctx = base.ContextWithMetadata(ctx, requestMetadata(ctx))
+		ctx, tx := client.Begin(ctx)
 
 		defer func() {
 			p := recover()
 			switch {
 			case p != nil:
-				tx.Rollback()
-				if tx.Error != nil {
-					log.Printf("error while rolling back the rpc request transaction during panic: %v", tx.Error.Error())
+				if tErr := tx.Rollback(); tErr != nil {
+					log.Printf("error while rolling back the rpc request transaction during panic: %v", tErr)
 				}
 				panic(p)
 			case err != nil:
-				tx.Rollback()
-				if tx.Error != nil {
-					log.Printf("error while rolling back the rpc request transaction: %v", tx.Error.Error())
+				if tErr := tx.Rollback(); tErr != nil {
+					log.Printf("error while rolling back the rpc request transaction: %v", tErr)
 				}
 			default:
-				tx.Commit()
-				if tx.Error != nil {
-					log.Printf("error while committing the rpc request transaction: %v", tx.Error.Error())
+				if tErr := tx.Commit(); tErr != nil {
+					log.Printf("error while committing the rpc request transaction: %v", tErr)
 				}
 			}
 		}()
@@ -36,3 +43,37 @@ func SessionUnaryInterceptor(client base.Client) grpc.UnaryServerInterceptor {
 		return handler(ctx, req)
 	}
 }
+
+// requestMetadata 从请求头读取事件元数据，未携带关联 ID 时以请求 ID 或新生成的 ID 作为关联 ID 和因果 ID
+func requestMetadata(ctx context.Context) base.Metadata {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	first := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+
+		return ""
+	}
+
+	requestID := first(requestIDHeader)
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+
+	correlationID := first(correlationIDHeader)
+	if correlationID == "" {
+		correlationID = requestID
+	}
+
+	causationID := first(causationIDHeader)
+	if causationID == "" {
+		causationID = requestID
+	}
+
+	return base.Metadata{
+		CorrelationID: correlationID,
+		CausationID:   causationID,
+		Actor:         first(actorHeader),
+	}
+}
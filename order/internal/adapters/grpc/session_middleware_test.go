@@ -0,0 +1,33 @@
+package grpc
+
+import (
+	"context"
+	"google.golang.org/grpc/metadata"
+	"testing"
+)
+
+func TestRequestMetadata(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		correlationIDHeader, "correlation",
+		causationIDHeader, "causation",
+		actorHeader, "alice",
+	))
+
+	m := requestMetadata(ctx)
+	if m.CorrelationID != "correlation" || m.CausationID != "causation" || m.Actor != "alice" {
+		t.Fatalf("expected metadata from headers, got %+v", m)
+	}
+
+	// 只有请求 ID 时作为关联 ID 和因果 ID
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestIDHeader, "request"))
+
+	m = requestMetadata(ctx)
+	if m.CorrelationID != "request" || m.CausationID != "request" || m.Actor != "" {
+		t.Fatalf("expected request ID as correlation and causation, got %+v", m)
+	}
+
+	m = requestMetadata(context.Background())
+	if m.CorrelationID == "" || m.CorrelationID != m.CausationID {
+		t.Fatalf("expected generated correlation and causation ID, got %+v", m)
+	}
+}
@@ -0,0 +1,32 @@
+package order
+
+import (
+	"context"
+	"order/internal/adapters/base"
+	"order/internal/application/core/domain"
+	"order/internal/ports"
+	"time"
+)
+
+var _ ports.OrderArchiveRepository = (*ArchiveAdapter)(nil)
+
+type ArchiveAdapter struct {
+	store *base.ArchiveStore
+	name  string
+}
+
+func NewArchiveAdapter(store *base.ArchiveStore) *ArchiveAdapter {
+	return &ArchiveAdapter{store: store, name: domain.NewOrder().EntityName()}
+}
+
+func (a *ArchiveAdapter) FindClosed(ctx context.Context, before time.Time, afterID string, limit int) ([]base.StreamVersion, error) {
+	return a.store.FindArchivable(ctx, a.name, domain.ClosedOrderEvents(), before, afterID, limit)
+}
+
+func (a *ArchiveAdapter) Archive(ctx context.Context, orderID string, version int) (bool, error) {
+	return a.store.Archive(ctx, a.name, orderID, version)
+}
+
+func (a *ArchiveAdapter) Restore(ctx context.Context, orderID string) error {
+	return a.store.Restore(ctx, a.name, orderID)
+}
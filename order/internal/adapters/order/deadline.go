@@ -0,0 +1,85 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"order/internal/adapters/base"
+	"order/internal/ports"
+	"time"
+)
+
+const (
+	DefaultDeadlineTableName = "order_deadlines"
+	scheduleDeadlineSQL      = `INSERT INTO %s (order_id, deadline, created_at) VALUES ($1, $2, %s)
+ON CONFLICT (order_id) DO UPDATE SET deadline = EXCLUDED.deadline`
+	nextDueDeadlineSQL      = "SELECT order_id FROM %s WHERE deadline <= $1 ORDER BY deadline ASC LIMIT 1 %s"
+	removeDeadlineSQL       = "DELETE FROM %s WHERE order_id = $1"
+	CreateDeadlinesTableSQL = `CREATE TABLE %s (
+		order_id   text        NOT NULL,
+		deadline   timestamptz NOT NULL,
+		created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (order_id)
+	)`
+	CreateSQLiteDeadlinesTableSQL = `CREATE TABLE %s (
+		order_id   text      NOT NULL,
+		deadline   timestamp NOT NULL,
+		created_at timestamp NOT NULL DEFAULT (strftime('%%Y-%%m-%%d %%H:%%M:%%f', 'now')),
+		PRIMARY KEY (order_id)
+	)`
+)
+
+var deadlinesTableDDL = base.DialectSQL{
+	base.PostgresDialectName: CreateDeadlinesTableSQL,
+	base.SQLiteDialectName:   CreateSQLiteDeadlinesTableSQL,
+}
+
+var _ ports.OrderDeadlineRepository = (*DeadlineAdapter)(nil)
+
+type DeadlineAdapter struct {
+	tableName string
+	client    base.Client
+}
+
+func NewDeadlineAdapter(client base.Client) *DeadlineAdapter {
+	a := &DeadlineAdapter{
+		tableName: DefaultDeadlineTableName,
+		client:    client,
+	}
+
+	err := client.Migrate(a.tableName, deadlinesTableDDL.For(client.Dialect()))
+	if err != nil {
+		panic(err)
+	}
+
+	return a
+}
+
+func (a *DeadlineAdapter) Schedule(ctx context.Context, orderID string, deadline time.Time) error {
+	dialect := a.client.Dialect()
+
+	return a.client.Exec(ctx, fmt.Sprintf(scheduleDeadlineSQL, a.tableName, dialect.CurrentTimestamp()), orderID, dialect.BindTime(deadline))
+}
+
+// NextDue 锁定一条已到期的记录，多个实例同时运行时互不重复处理；SQLite 不支持行锁，写事务本身串行执行
+func (a *DeadlineAdapter) NextDue(ctx context.Context, now time.Time) (string, bool, error) {
+	dialect := a.client.Dialect()
+	row := a.client.QueryRow(ctx, fmt.Sprintf(nextDueDeadlineSQL, a.tableName, dialect.SkipLocked()), dialect.BindTime(now))
+
+	var orderID string
+
+	err := row.Scan(&orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	return orderID, true, nil
+}
+
+func (a *DeadlineAdapter) Remove(ctx context.Context, orderID string) error {
+	return a.client.Exec(ctx, fmt.Sprintf(removeDeadlineSQL, a.tableName), orderID)
+}
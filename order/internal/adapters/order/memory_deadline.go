@@ -0,0 +1,79 @@
+package order
+
+import (
+	"context"
+	"order/internal/adapters/base"
+	"order/internal/ports"
+	"sync"
+	"time"
+)
+
+var _ ports.OrderDeadlineRepository = (*MemoryDeadlineAdapter)(nil)
+
+// MemoryDeadlineAdapter 与 base.NewMemoryClient 配合使用，数据保存在进程内存中
+type MemoryDeadlineAdapter struct {
+	mu        sync.Mutex
+	deadlines map[string]time.Time
+}
+
+func NewMemoryDeadlineAdapter() *MemoryDeadlineAdapter {
+	return &MemoryDeadlineAdapter{deadlines: make(map[string]time.Time)}
+}
+
+func (a *MemoryDeadlineAdapter) Schedule(ctx context.Context, orderID string, deadline time.Time) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.set(ctx, orderID, deadline, true)
+
+	return nil
+}
+
+// NextDue 内存事务依次执行，返回最早到期的记录即可避免重复处理
+func (a *MemoryDeadlineAdapter) NextDue(_ context.Context, now time.Time) (string, bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var next string
+	var nextDeadline time.Time
+	for orderID, deadline := range a.deadlines {
+		if deadline.After(now) {
+			continue
+		}
+		if next == "" || deadline.Before(nextDeadline) {
+			next, nextDeadline = orderID, deadline
+		}
+	}
+
+	return next, next != "", nil
+}
+
+func (a *MemoryDeadlineAdapter) Remove(ctx context.Context, orderID string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.set(ctx, orderID, time.Time{}, false)
+
+	return nil
+}
+
+// set 修改到期时间并登记回滚时的恢复操作，调用方持有锁
+func (a *MemoryDeadlineAdapter) set(ctx context.Context, orderID string, deadline time.Time, ok bool) {
+	previous, existed := a.deadlines[orderID]
+	if ok {
+		a.deadlines[orderID] = deadline
+	} else {
+		delete(a.deadlines, orderID)
+	}
+
+	base.OnRollback(ctx, func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+
+		if existed {
+			a.deadlines[orderID] = previous
+		} else {
+			delete(a.deadlines, orderID)
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"order/internal/adapters/base"
 	"order/internal/application/core/domain"
 	"order/internal/ports"
+	"time"
 )
 
 var _ ports.OrderRepository = (*Adapter)(nil)
@@ -15,12 +16,64 @@ type Adapter struct {
 	store base.AggregateRepository
 }
 
-func NewAdapter(store base.Store) *Adapter {
-	return &Adapter{store: base.NewAggregateRootRepository(domain.NewOrder, store)}
+type adapterOptions struct {
+	orderOptions      []domain.OrderOption
+	repositoryOptions []base.AggregateRootRepositoryOption
+}
+
+type AdapterOption func(*adapterOptions)
+
+func WithOrderOptions(options ...domain.OrderOption) AdapterOption {
+	return func(o *adapterOptions) {
+		o.orderOptions = append(o.orderOptions, options...)
+	}
+}
+
+// WithRetryPolicy 版本冲突时重新加载订单并重试命令
+func WithRetryPolicy(policy base.RetryPolicy) AdapterOption {
+	return func(o *adapterOptions) {
+		o.repositoryOptions = append(o.repositoryOptions, base.WithRetryPolicy(policy))
+	}
+}
+
+func NewAdapter(store base.Store, options ...AdapterOption) *Adapter {
+	adapterOptions := &adapterOptions{}
+	for _, option := range options {
+		option(adapterOptions)
+	}
+
+	constructor := func() base.Aggregate {
+		return domain.NewOrder(adapterOptions.orderOptions...)
+	}
+
+	return &Adapter{store: base.NewAggregateRootRepository(constructor, store, adapterOptions.repositoryOptions...)}
 }
 
 func (a *Adapter) Load(ctx context.Context, aggregateID string) (*domain.Order, error) {
-	root, err := a.store.Load(ctx, aggregateID)
+	return a.load(a.store.Load(ctx, aggregateID))
+}
+
+func (a *Adapter) LoadAtVersion(ctx context.Context, aggregateID string, version int) (*domain.Order, error) {
+	return a.load(a.store.LoadAtVersion(ctx, aggregateID, version))
+}
+
+func (a *Adapter) LoadAsOf(ctx context.Context, aggregateID string, asOf time.Time) (*domain.Order, error) {
+	return a.load(a.store.LoadAsOf(ctx, aggregateID, asOf))
+}
+
+func (a *Adapter) load(root *base.AggregateRoot, err error) (*domain.Order, error) {
+	if err != nil {
+		if errors.Is(err, base.ErrAggregateNotFound) {
+			return nil, fmt.Errorf("order not found: %w", err)
+		}
+		return nil, err
+	}
+
+	return root.Aggregate().(*domain.Order), nil
+}
+
+func (a *Adapter) Execute(ctx context.Context, aggregateID string, command base.Command, options ...base.AggregateRootOption) (*domain.Order, error) {
+	root, err := a.store.Execute(ctx, aggregateID, command, options...)
 	if err != nil {
 		if errors.Is(err, base.ErrAggregateNotFound) {
 			return nil, fmt.Errorf("order not found: %w", err)
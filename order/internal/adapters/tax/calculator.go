@@ -0,0 +1,146 @@
+package tax
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"math"
+	"order/internal/application/core/domain"
+	"order/internal/ports"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultRegion 税率表中未列出的地区使用该地区的税率
+const DefaultRegion = "*"
+
+var ErrInvalidRateTable = errors.New("invalid tax rate table")
+
+var _ ports.TaxCalculator = (*Adapter)(nil)
+
+type Adapter struct {
+	// 地区 -> 税类 -> 万分比税率
+	regions map[string]map[string]int64
+}
+
+// NewAdapter 读取 YAML 或 CSV 格式的税率表，path 为空时不计税
+//
+// YAML 格式:
+//
+//	CN-31:
+//	  standard: 0.13
+//	  food: 0.09
+//
+// CSV 格式:
+//
+//	region,tax_class,rate
+//	CN-31,standard,0.13
+func NewAdapter(path string) (*Adapter, error) {
+	a := &Adapter{}
+	if path == "" {
+		return a, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var table map[string]map[string]float64
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		table, err = readYAML(file)
+	case ".csv":
+		table, err = readCSV(file)
+	default:
+		return nil, fmt.Errorf("%w: unsupported file %s", ErrInvalidRateTable, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.regions = make(map[string]map[string]int64, len(table))
+	for region, classes := range table {
+		rates := make(map[string]int64, len(classes))
+		for taxClass, rate := range classes {
+			if rate < 0 || rate > 1 {
+				return nil, fmt.Errorf("%w: region %s tax class %s rate %v out of range", ErrInvalidRateTable, region, taxClass, rate)
+			}
+
+			rates[taxClass] = int64(math.Round(rate * 10000))
+		}
+
+		a.regions[region] = rates
+	}
+
+	return a, nil
+}
+
+func (a *Adapter) TaxRates(ctx context.Context, region string) (domain.TaxRates, error) {
+	if a.regions == nil {
+		return domain.TaxRates{Region: region}, nil
+	}
+
+	rates, ok := a.regions[region]
+	if !ok {
+		rates, ok = a.regions[DefaultRegion]
+	}
+	if !ok {
+		return domain.TaxRates{}, fmt.Errorf("%w: region %q", domain.ErrTaxRateNotFound, region)
+	}
+
+	copied := make(map[string]int64, len(rates))
+	for taxClass, rate := range rates {
+		copied[taxClass] = rate
+	}
+
+	return domain.TaxRates{Region: region, Rates: copied}, nil
+}
+
+func readYAML(r io.Reader) (map[string]map[string]float64, error) {
+	var table map[string]map[string]float64
+
+	err := yaml.NewDecoder(r).Decode(&table)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRateTable, err)
+	}
+
+	return table, nil
+}
+
+func readCSV(r io.Reader) (map[string]map[string]float64, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRateTable, err)
+	}
+
+	table := make(map[string]map[string]float64)
+	for i, record := range records {
+		if len(record) != 3 {
+			return nil, fmt.Errorf("%w: line %d has %d columns", ErrInvalidRateTable, i+1, len(record))
+		}
+
+		if i == 0 && record[0] == "region" {
+			continue
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidRateTable, i+1, err)
+		}
+
+		region := strings.TrimSpace(record[0])
+		if table[region] == nil {
+			table[region] = make(map[string]float64)
+		}
+		table[region][strings.TrimSpace(record[1])] = rate
+	}
+
+	return table, nil
+}
@@ -2,21 +2,41 @@ package application
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"order/internal/adapters/base"
 	"order/internal/application/core/domain"
 	"order/internal/application/core/dto"
 	"order/internal/ports"
+	"strings"
+	"time"
 )
 
 var _ ports.Application = (*Application)(nil)
 
 type Application struct {
-	orderRepo ports.OrderRepository
+	orderRepo      ports.OrderRepository
+	taxCalculator  ports.TaxCalculator
+	orderDeadlines ports.OrderDeadlineRepository
+	customerKeys   ports.CustomerKeyRepository
+	orderArchive   ports.OrderArchiveRepository
+	orderTTL       time.Duration
 }
 
-func NewApplication(orderRepo ports.OrderRepository) *Application {
-	return &Application{
-		orderRepo: orderRepo,
+func NewApplication(orderRepo ports.OrderRepository, taxCalculator ports.TaxCalculator, orderDeadlines ports.OrderDeadlineRepository, customerKeys ports.CustomerKeyRepository, options ...ApplicationOption) *Application {
+	app := &Application{
+		orderRepo:      orderRepo,
+		taxCalculator:  taxCalculator,
+		orderDeadlines: orderDeadlines,
+		customerKeys:   customerKeys,
 	}
+
+	for _, option := range options {
+		option(app)
+	}
+
+	return app
 }
 
 func (app *Application) CreateOrder(ctx context.Context, dto dto.CreateOrderDTO) (domain.Order, error) {
@@ -26,20 +46,302 @@ func (app *Application) CreateOrder(ctx context.Context, dto dto.CreateOrderDTO)
 			ProductId: item.ProductId,
 			Price:     item.Price,
 			Number:    item.Number,
+			TaxClass:  item.TaxClass,
 		})
 	}
 
+	taxRates, err := app.taxCalculator.TaxRates(ctx, dto.DeliveryAddress.Region)
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	var expiresAt *time.Time
+	ttl := dto.TTL
+	if ttl <= 0 {
+		ttl = app.orderTTL
+	}
+	if ttl > 0 {
+		deadline := time.Now().Add(ttl)
+		expiresAt = &deadline
+	}
+
 	order, err := app.orderRepo.Save(ctx, &domain.CreateOrder{
-		CustomerID: dto.CustomerID,
-		OrderItems: orderItems,
+		CustomerID:      dto.CustomerID,
+		OrderItems:      orderItems,
+		DeliveryAddress: dto.DeliveryAddress,
+		TaxRates:        taxRates,
+		ExpiresAt:       expiresAt,
 	})
 	if err != nil {
 		return domain.Order{}, err
 	}
 
+	if expiresAt != nil {
+		err = app.orderDeadlines.Schedule(ctx, order.ID(), *expiresAt)
+		if err != nil {
+			return domain.Order{}, err
+		}
+	}
+
 	return *order, nil
 }
 
 func (app *Application) GetOrder(ctx context.Context, aggregateID string) (*domain.Order, error) {
 	return app.orderRepo.Load(ctx, aggregateID)
 }
+
+// GetOrderAtVersion 订单在指定版本时的状态
+func (app *Application) GetOrderAtVersion(ctx context.Context, aggregateID string, version int) (*domain.Order, error) {
+	return app.orderRepo.LoadAtVersion(ctx, aggregateID, version)
+}
+
+// GetOrderAsOf 订单在 asOf 时的状态，订单当时尚未创建时返回未找到
+func (app *Application) GetOrderAsOf(ctx context.Context, aggregateID string, asOf time.Time) (*domain.Order, error) {
+	return app.orderRepo.LoadAsOf(ctx, aggregateID, asOf)
+}
+
+func (app *Application) ApproveOrder(ctx context.Context, aggregateID string) error {
+	_, err := app.orderRepo.Execute(ctx, aggregateID, &domain.ApproveOrder{})
+
+	return err
+}
+
+func (app *Application) RejectOrder(ctx context.Context, aggregateID string, reason string) error {
+	_, err := app.orderRepo.Execute(ctx, aggregateID, &domain.RejectOrder{Reason: reason})
+
+	return err
+}
+
+// ExpireOrder 加载订单后执行超时拒绝；订单已不在待确认状态、已不存在或不再有到期时间时，仅清除到期记录
+//
+// 订单尚未到期（到期时间被推迟或时钟偏差）时，按订单当前的到期时间重新安排
+func (app *Application) ExpireOrder(ctx context.Context, aggregateID string, now time.Time) error {
+	_, err := app.orderRepo.Execute(ctx, aggregateID, &domain.ExpireOrder{Now: now})
+
+	var notExpired *domain.OrderNotExpiredError
+	if errors.As(err, &notExpired) && notExpired.ExpiresAt != nil {
+		return app.orderDeadlines.Schedule(ctx, aggregateID, *notExpired.ExpiresAt)
+	}
+
+	if err != nil && !errors.Is(err, domain.ErrOrderNotExpired) && !errors.Is(err, domain.ErrOrderInvalidState) && !errors.Is(err, base.ErrAggregateNotFound) {
+		return err
+	}
+
+	return app.orderDeadlines.Remove(ctx, aggregateID)
+}
+
+func (app *Application) BeginCancelOrder(ctx context.Context, aggregateID string) error {
+	_, err := app.orderRepo.Execute(ctx, aggregateID, &domain.BeginCancelOrder{})
+
+	return err
+}
+
+func (app *Application) ConfirmCancelOrder(ctx context.Context, aggregateID string) error {
+	_, err := app.orderRepo.Execute(ctx, aggregateID, &domain.ConfirmCancelOrder{})
+
+	return err
+}
+
+func (app *Application) UndoCancelOrder(ctx context.Context, aggregateID string) error {
+	_, err := app.orderRepo.Execute(ctx, aggregateID, &domain.UndoCancelOrder{})
+
+	return err
+}
+
+func (app *Application) ReviseOrder(ctx context.Context, aggregateID string, dto dto.ReviseOrderDTO) (domain.Order, error) {
+	var itemDeltas []domain.OrderItemDelta
+	for _, itemDelta := range dto.ItemDeltas {
+		itemDeltas = append(itemDeltas, domain.OrderItemDelta{
+			ProductId: itemDelta.ProductId,
+			Delta:     itemDelta.Delta,
+		})
+	}
+
+	order, err := app.orderRepo.Execute(ctx, aggregateID, &domain.ReviseOrder{
+		ItemDeltas: itemDeltas,
+	})
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	return *order, nil
+}
+
+func (app *Application) ConfirmRevision(ctx context.Context, aggregateID string) error {
+	_, err := app.orderRepo.Execute(ctx, aggregateID, &domain.ConfirmRevision{})
+
+	return err
+}
+
+func (app *Application) RejectRevision(ctx context.Context, aggregateID string) error {
+	_, err := app.orderRepo.Execute(ctx, aggregateID, &domain.RejectRevision{})
+
+	return err
+}
+
+func (app *Application) ChangeDeliveryAddress(ctx context.Context, aggregateID string, address domain.Address) error {
+	taxRates, err := app.taxCalculator.TaxRates(ctx, address.Region)
+	if err != nil {
+		return err
+	}
+
+	_, err = app.orderRepo.Execute(ctx, aggregateID, &domain.ChangeDeliveryAddress{
+		DeliveryAddress: address,
+		TaxRates:        taxRates,
+	})
+
+	return err
+}
+
+func (app *Application) ApplyCoupon(ctx context.Context, aggregateID string, coupon domain.Coupon) (domain.Order, error) {
+	order, err := app.orderRepo.Execute(ctx, aggregateID, &domain.ApplyCoupon{Coupon: coupon})
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	return *order, nil
+}
+
+func (app *Application) RemoveCoupon(ctx context.Context, aggregateID string, code string) (domain.Order, error) {
+	order, err := app.orderRepo.Execute(ctx, aggregateID, &domain.RemoveCoupon{Code: code})
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	return *order, nil
+}
+
+func (app *Application) RecordShipment(ctx context.Context, aggregateID string, dto dto.RecordShipmentDTO) (domain.Order, error) {
+	var items []domain.ShipmentItem
+	for _, item := range dto.Items {
+		items = append(items, domain.ShipmentItem{
+			ProductId: item.ProductId,
+			Number:    item.Number,
+		})
+	}
+
+	order, err := app.orderRepo.Execute(ctx, aggregateID, &domain.RecordShipment{
+		Shipment: domain.Shipment{
+			Carrier:        dto.Carrier,
+			TrackingNumber: dto.TrackingNumber,
+			Items:          items,
+			ShippedAt:      time.Now(),
+		},
+	})
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	return *order, nil
+}
+
+func (app *Application) RequestReturn(ctx context.Context, aggregateID string, dto dto.ReturnDTO) (string, error) {
+	returnID := uuid.New().String()
+
+	_, err := app.orderRepo.Execute(ctx, aggregateID, &domain.RequestReturn{
+		ReturnID:   returnID,
+		ReasonCode: dto.ReasonCode,
+		Items:      toReturnLines(dto.Items),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return returnID, nil
+}
+
+func (app *Application) ReceiveReturn(ctx context.Context, aggregateID string, returnID string, dto dto.ReturnDTO) error {
+	_, err := app.orderRepo.Execute(ctx, aggregateID, &domain.ReceiveReturn{
+		ReturnID:   returnID,
+		ReasonCode: dto.ReasonCode,
+		Items:      toReturnLines(dto.Items),
+	})
+
+	return err
+}
+
+func (app *Application) IssueRefund(ctx context.Context, aggregateID string, returnID string, dto dto.ReturnDTO) (domain.Return, error) {
+	order, err := app.orderRepo.Execute(ctx, aggregateID, &domain.IssueRefund{
+		ReturnID:   returnID,
+		ReasonCode: dto.ReasonCode,
+		Items:      toReturnLines(dto.Items),
+	})
+	if err != nil {
+		return domain.Return{}, err
+	}
+
+	for _, r := range order.Returns {
+		if r.ID == returnID {
+			return r, nil
+		}
+	}
+
+	return domain.Return{}, fmt.Errorf("%w: return %s not found", domain.ErrOrderInvalidReturn, returnID)
+}
+
+// ForgetCustomer 销毁客户的密钥，该客户的个人数据此后在订单中显示为 base.Redacted，订单其余数据不受影响
+func (app *Application) ForgetCustomer(ctx context.Context, customerID string) error {
+	if strings.TrimSpace(customerID) == "" {
+		violations := &domain.ValidationError{}
+		violations.Add("customer_id", "must not be empty")
+		return violations
+	}
+
+	return app.customerKeys.Forget(ctx, customerID)
+}
+
+// ArchiveOrder 订单已结束时将其移到归档存储，订单未结束或已不在 version 时返回 false
+func (app *Application) ArchiveOrder(ctx context.Context, aggregateID string, version int) (bool, error) {
+	if app.orderArchive == nil {
+		return false, nil
+	}
+
+	order, err := app.orderRepo.Load(ctx, aggregateID)
+	if err != nil {
+		return false, err
+	}
+
+	if !order.Closed() {
+		return false, nil
+	}
+
+	return app.orderArchive.Archive(ctx, aggregateID, version)
+}
+
+// RestoreOrder 将归档的订单移回热存储，订单未归档时不做任何操作
+func (app *Application) RestoreOrder(ctx context.Context, aggregateID string) error {
+	_, err := app.orderRepo.Load(ctx, aggregateID)
+	if err != nil || app.orderArchive == nil {
+		return err
+	}
+
+	return app.orderArchive.Restore(ctx, aggregateID)
+}
+
+func toReturnLines(items []dto.ReturnItemDTO) []domain.ReturnLine {
+	var lines []domain.ReturnLine
+	for _, item := range items {
+		lines = append(lines, domain.ReturnLine{
+			ProductId: item.ProductId,
+			Number:    item.Number,
+		})
+	}
+
+	return lines
+}
+
+type ApplicationOption func(*Application)
+
+// WithOrderTTL 订单默认的确认超时时间，为 0 时订单不会超时
+func WithOrderTTL(ttl time.Duration) ApplicationOption {
+	return func(app *Application) {
+		app.orderTTL = ttl
+	}
+}
+
+// WithOrderArchive 归档已结束的订单，未设置时订单不会被归档
+func WithOrderArchive(archive ports.OrderArchiveRepository) ApplicationOption {
+	return func(app *Application) {
+		app.orderArchive = archive
+	}
+}
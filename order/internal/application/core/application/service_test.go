@@ -0,0 +1,176 @@
+package application
+
+import (
+	"context"
+	"order/internal/adapters/base"
+	orderAdapter "order/internal/adapters/order"
+	"order/internal/application/core/domain"
+	"order/internal/application/core/dto"
+	"sort"
+	"testing"
+	"time"
+)
+
+// testStore 按聚合根 ID 在内存中保存事件
+type testStore struct {
+	events map[string][]base.Event
+}
+
+func (s *testStore) Load(ctx context.Context, root *base.AggregateRoot) error {
+	return root.LoadEvent(s.events[root.ID()][root.PendingVersion():]...)
+}
+
+func (s *testStore) Save(ctx context.Context, root *base.AggregateRoot) error {
+	s.events[root.ID()] = append(s.events[root.ID()], root.Events()...)
+
+	return nil
+}
+
+type testTaxCalculator struct{}
+
+func (testTaxCalculator) TaxRates(ctx context.Context, region string) (domain.TaxRates, error) {
+	return domain.TaxRates{}, nil
+}
+
+type testDeadlines struct {
+	deadlines map[string]time.Time
+}
+
+func (d *testDeadlines) Schedule(ctx context.Context, orderID string, deadline time.Time) error {
+	d.deadlines[orderID] = deadline
+
+	return nil
+}
+
+func (d *testDeadlines) NextDue(ctx context.Context, now time.Time) (string, bool, error) {
+	var due []string
+	for orderID, deadline := range d.deadlines {
+		if !deadline.After(now) {
+			due = append(due, orderID)
+		}
+	}
+	if len(due) == 0 {
+		return "", false, nil
+	}
+
+	sort.Slice(due, func(i, j int) bool { return d.deadlines[due[i]].Before(d.deadlines[due[j]]) })
+
+	return due[0], true, nil
+}
+
+func (d *testDeadlines) Remove(ctx context.Context, orderID string) error {
+	delete(d.deadlines, orderID)
+
+	return nil
+}
+
+func TestExpireOrder(t *testing.T) {
+	ctx := context.Background()
+	store := &testStore{events: map[string][]base.Event{}}
+	deadlines := &testDeadlines{deadlines: map[string]time.Time{}}
+	app := NewApplication(orderAdapter.NewAdapter(store), testTaxCalculator{}, deadlines, nil, WithOrderTTL(50*time.Millisecond))
+
+	create := func() string {
+		t.Helper()
+
+		order, err := app.CreateOrder(ctx, dto.CreateOrderDTO{
+			CustomerID: "c1",
+			OrderItems: []dto.CreateOrderItemDTO{{ProductId: "p1", Price: domain.NewMoney(100, "CNY"), Number: 1}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return order.ID()
+	}
+
+	pending, approved, rejected := create(), create(), create()
+	if len(deadlines.deadlines) != 3 {
+		t.Fatalf("expected 3 deadlines, got %v", deadlines.deadlines)
+	}
+
+	err := app.ApproveOrder(ctx, approved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = app.RejectOrder(ctx, rejected, "out of stock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 未到期时不处理任何订单
+	_, ok, _ := deadlines.NextDue(ctx, time.Now())
+	if ok {
+		t.Fatal("expected no due deadline before the ttl")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	// 与 Worker 相同，逐条处理到期记录直到没有到期记录
+	for {
+		orderID, ok, err := deadlines.NextDue(ctx, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+
+		err = app.ExpireOrder(ctx, orderID, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(deadlines.deadlines) != 0 {
+		t.Fatalf("expected all deadlines removed, got %v", deadlines.deadlines)
+	}
+
+	lastEvent := func(orderID string) base.Event {
+		events := store.events[orderID]
+		return events[len(events)-1]
+	}
+
+	rejectedEvent, ok := lastEvent(pending).(*domain.OrderRejected)
+	if !ok || rejectedEvent.Reason != domain.ExpiredRejectReason {
+		t.Fatalf("expected pending order to be rejected as expired, got %#v", lastEvent(pending))
+	}
+	if _, ok := lastEvent(approved).(*domain.OrderApproved); !ok || len(store.events[approved]) != 2 {
+		t.Fatalf("expected approved order to be left alone, got %d events", len(store.events[approved]))
+	}
+	if event, ok := lastEvent(rejected).(*domain.OrderRejected); !ok || event.Reason != "out of stock" || len(store.events[rejected]) != 2 {
+		t.Fatalf("expected rejected order to be left alone, got %d events", len(store.events[rejected]))
+	}
+}
+
+func TestExpireOrderNotDue(t *testing.T) {
+	ctx := context.Background()
+	store := &testStore{events: map[string][]base.Event{}}
+	deadlines := &testDeadlines{deadlines: map[string]time.Time{}}
+	app := NewApplication(orderAdapter.NewAdapter(store), testTaxCalculator{}, deadlines, nil)
+
+	order, err := app.CreateOrder(ctx, dto.CreateOrderDTO{
+		CustomerID: "c1",
+		OrderItems: []dto.CreateOrderItemDTO{{ProductId: "p1", Price: domain.NewMoney(100, "CNY"), Number: 1}},
+		TTL:        time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiresAt := deadlines.deadlines[order.ID()]
+
+	// 到期记录提前触发时按订单的到期时间重新安排
+	deadlines.deadlines[order.ID()] = time.Now()
+	err = app.ExpireOrder(ctx, order.ID(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !deadlines.deadlines[order.ID()].Equal(expiresAt) {
+		t.Fatalf("expected deadline rescheduled to %v, got %v", expiresAt, deadlines.deadlines[order.ID()])
+	}
+	if len(store.events[order.ID()]) != 1 {
+		t.Fatalf("expected order to stay pending, got %d events", len(store.events[order.ID()]))
+	}
+}
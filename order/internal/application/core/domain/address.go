@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Address 收货地址，除地区外均为个人数据，存储时加密
+type Address struct {
+	Recipient  string   `json:"recipient" pii:"true"`
+	Phone      string   `json:"phone" pii:"true"`
+	Region     string   `json:"region"`
+	PostalCode string   `json:"postal_code" pii:"true"`
+	Lines      []string `json:"lines" pii:"true"`
+}
+
+func (a Address) IsZero() bool {
+	return a.Recipient == "" && a.Phone == "" && a.Region == "" && a.PostalCode == "" && len(a.Lines) == 0
+}
+
+func (a Address) validate(field string, violations *ValidationError) {
+	if strings.TrimSpace(a.Recipient) == "" {
+		violations.Add(field+".recipient", "must not be empty")
+	}
+
+	if strings.TrimSpace(a.Phone) == "" {
+		violations.Add(field+".phone", "must not be empty")
+	}
+
+	if strings.TrimSpace(a.Region) == "" {
+		violations.Add(field+".region", "must not be empty")
+	}
+
+	if len(a.Lines) == 0 {
+		violations.Add(field+".lines", "must contain at least one line")
+	}
+
+	for i, line := range a.Lines {
+		if strings.TrimSpace(line) == "" {
+			violations.Add(fmt.Sprintf("%s.lines[%d]", field, i), "must not be empty")
+		}
+	}
+}
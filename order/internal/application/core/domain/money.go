@@ -0,0 +1,206 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// DefaultCurrency 未携带币种的历史数据按人民币处理
+const DefaultCurrency = "CNY"
+
+var (
+	ErrMoneyCurrencyMismatch = errors.New("money currency mismatch")
+	ErrMoneyInvalidCurrency  = errors.New("money currency is invalid")
+	ErrMoneyOverflow         = errors.New("money amount overflow")
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// currencyExponents ISO 4217 最小货币单位的小数位数，未列出的币种为 2 位
+var currencyExponents = map[string]int{
+	"BHD": 3,
+	"CLP": 0,
+	"IQD": 3,
+	"ISK": 0,
+	"JOD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"TND": 3,
+	"VND": 0,
+}
+
+type RoundingMode int
+
+const (
+	RoundHalfUp   RoundingMode = iota // 四舍五入，.5 远离零
+	RoundHalfEven                     // 银行家舍入
+	RoundDown                         // 向零截断
+)
+
+// Money 金额，以最小货币单位（如分）的整数存储
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// NewMoneyFromFloat 将以主货币单位表示的浮点数按指定舍入方式转换为金额
+func NewMoneyFromFloat(amount float64, currency string, mode RoundingMode) (Money, error) {
+	if !currencyPattern.MatchString(currency) {
+		return Money{}, fmt.Errorf("%w: %q", ErrMoneyInvalidCurrency, currency)
+	}
+
+	scaled := amount * math.Pow10(currencyExponent(currency))
+	switch mode {
+	case RoundHalfEven:
+		scaled = math.RoundToEven(scaled)
+	case RoundDown:
+		scaled = math.Trunc(scaled)
+	default:
+		scaled = math.Round(scaled)
+	}
+
+	if math.IsNaN(scaled) || scaled >= math.MaxInt64 || scaled <= math.MinInt64 {
+		return Money{}, fmt.Errorf("%w: %v %s", ErrMoneyOverflow, amount, currency)
+	}
+
+	return NewMoney(int64(scaled), currency), nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrMoneyCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrMoneyOverflow, m, other)
+	}
+
+	return NewMoney(sum, m.Currency), nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if other.Amount == math.MinInt64 {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrMoneyOverflow, m, other)
+	}
+
+	return m.Add(NewMoney(-other.Amount, other.Currency))
+}
+
+func (m Money) Mul(n int64) (Money, error) {
+	if n == 0 || m.Amount == 0 {
+		return NewMoney(0, m.Currency), nil
+	}
+
+	product := m.Amount * n
+	if product/n != m.Amount || (m.Amount == -1 && n == math.MinInt64) || (n == -1 && m.Amount == math.MinInt64) {
+		return Money{}, fmt.Errorf("%w: %s * %d", ErrMoneyOverflow, m, n)
+	}
+
+	return NewMoney(product, m.Currency), nil
+}
+
+// MulRatio 按 numerator/denominator 缩放金额，余数按指定舍入方式处理
+func (m Money) MulRatio(numerator, denominator int64, mode RoundingMode) (Money, error) {
+	if denominator == 0 {
+		return Money{}, fmt.Errorf("%w: division by zero", ErrMoneyOverflow)
+	}
+
+	n := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(numerator))
+	d := big.NewInt(denominator)
+	if d.Sign() < 0 {
+		n.Neg(n)
+		d.Neg(d)
+	}
+
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Sign() != 0 && mode != RoundDown {
+		// 比较 2|r| 与 d 判断是否过半
+		cmp := new(big.Int).Mul(new(big.Int).Abs(r), big.NewInt(2)).Cmp(d)
+		if cmp > 0 || (cmp == 0 && (mode == RoundHalfUp || q.Bit(0) == 1)) {
+			q.Add(q, big.NewInt(int64(n.Sign())))
+		}
+	}
+
+	if !q.IsInt64() {
+		return Money{}, fmt.Errorf("%w: %s * %d / %d", ErrMoneyOverflow, m, numerator, denominator)
+	}
+
+	return NewMoney(q.Int64(), m.Currency), nil
+}
+
+// Float64 以主货币单位返回金额，仅用于展示或兼容旧接口
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / math.Pow10(currencyExponent(m.Currency))
+}
+
+func (m Money) String() string {
+	exponent := currencyExponent(m.Currency)
+	if exponent == 0 {
+		return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+	}
+
+	sign := ""
+	amount := new(big.Int).SetInt64(m.Amount)
+	if amount.Sign() < 0 {
+		sign = "-"
+		amount.Neg(amount)
+	}
+
+	digits := fmt.Sprintf("%0*s", exponent+1, amount.String())
+	split := len(digits) - exponent
+
+	return fmt.Sprintf("%s%s.%s %s", sign, digits[:split], digits[split:], m.Currency)
+}
+
+// UnmarshalJSON 兼容历史数据中以浮点数存储的金额
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] == '{' || bytes.Equal(data, []byte("null")) {
+		type money Money
+		return json.Unmarshal(data, (*money)(m))
+	}
+
+	var amount float64
+	err := json.Unmarshal(data, &amount)
+	if err != nil {
+		return err
+	}
+
+	legacy, err := NewMoneyFromFloat(amount, DefaultCurrency, RoundHalfUp)
+	if err != nil {
+		return err
+	}
+
+	*m = legacy
+
+	return nil
+}
+
+func currencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[strings.ToUpper(currency)]; ok {
+		return exponent
+	}
+
+	return 2
+}
@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMoneyArithmetic(t *testing.T) {
+	cny := func(amount int64) Money { return NewMoney(amount, "CNY") }
+	usd := NewMoney(100, "USD")
+
+	tests := []struct {
+		name string
+		op   func() (Money, error)
+		want Money
+		err  error
+	}{
+		{"add", func() (Money, error) { return cny(150).Add(cny(250)) }, cny(400), nil},
+		{"add currency mismatch", func() (Money, error) { return cny(150).Add(usd) }, Money{}, ErrMoneyCurrencyMismatch},
+		{"add overflow", func() (Money, error) { return cny(math.MaxInt64).Add(cny(1)) }, Money{}, ErrMoneyOverflow},
+		{"add negative overflow", func() (Money, error) { return cny(math.MinInt64).Add(cny(-1)) }, Money{}, ErrMoneyOverflow},
+		{"sub", func() (Money, error) { return cny(150).Sub(cny(250)) }, cny(-100), nil},
+		{"sub currency mismatch", func() (Money, error) { return cny(150).Sub(usd) }, Money{}, ErrMoneyCurrencyMismatch},
+		{"sub overflow", func() (Money, error) { return cny(0).Sub(cny(math.MinInt64)) }, Money{}, ErrMoneyOverflow},
+		{"mul", func() (Money, error) { return cny(250).Mul(3) }, cny(750), nil},
+		{"mul zero", func() (Money, error) { return cny(math.MaxInt64).Mul(0) }, cny(0), nil},
+		{"mul overflow", func() (Money, error) { return cny(math.MaxInt64 / 2).Mul(3) }, Money{}, ErrMoneyOverflow},
+		{"mul min int overflow", func() (Money, error) { return cny(math.MinInt64).Mul(-1) }, Money{}, ErrMoneyOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.op()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMoneyMulRatio(t *testing.T) {
+	tests := []struct {
+		name        string
+		amount      int64
+		numerator   int64
+		denominator int64
+		mode        RoundingMode
+		want        int64
+	}{
+		{"exact", 300, 1, 3, RoundHalfUp, 100},
+		{"half up", 5, 1, 2, RoundHalfUp, 3},
+		{"half up negative", -5, 1, 2, RoundHalfUp, -3},
+		{"half even rounds down to even", 5, 1, 2, RoundHalfEven, 2},
+		{"half even rounds up to even", 7, 1, 2, RoundHalfEven, 4},
+		{"above half", 200, 1, 3, RoundHalfEven, 67},
+		{"below half", 100, 1, 3, RoundHalfUp, 33},
+		{"down", 199, 1, 2, RoundDown, 99},
+		{"down negative", -199, 1, 2, RoundDown, -99},
+		{"negative denominator", 5, 1, -2, RoundHalfUp, -3},
+		{"wide intermediate", math.MaxInt64, 3, 4, RoundDown, 6917529027641081855},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMoney(tt.amount, "CNY").MulRatio(tt.numerator, tt.denominator, tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Amount != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, got.Amount)
+			}
+		})
+	}
+
+	_, err := NewMoney(100, "CNY").MulRatio(1, 0, RoundHalfUp)
+	if !errors.Is(err, ErrMoneyOverflow) {
+		t.Fatalf("expected ErrMoneyOverflow on zero denominator, got %v", err)
+	}
+
+	_, err = NewMoney(math.MaxInt64, "CNY").MulRatio(2, 1, RoundHalfUp)
+	if !errors.Is(err, ErrMoneyOverflow) {
+		t.Fatalf("expected ErrMoneyOverflow, got %v", err)
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{NewMoney(12345, "CNY"), "123.45 CNY"},
+		{NewMoney(5, "CNY"), "0.05 CNY"},
+		{NewMoney(-5, "CNY"), "-0.05 CNY"},
+		{NewMoney(0, "USD"), "0.00 USD"},
+		{NewMoney(1500, "JPY"), "1500 JPY"},
+		{NewMoney(-1500, "JPY"), "-1500 JPY"},
+		{NewMoney(1234, "KWD"), "1.234 KWD"},
+		{NewMoney(math.MinInt64, "CNY"), "-92233720368547758.08 CNY"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestMoneyFromFloat(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		mode     RoundingMode
+		want     int64
+	}{
+		{12.345, "CNY", RoundHalfUp, 1235},
+		{0.125, "CNY", RoundHalfEven, 12},
+		{12.349, "CNY", RoundDown, 1234},
+		{1500.6, "JPY", RoundHalfUp, 1501},
+		{1.2345, "KWD", RoundDown, 1234},
+	}
+
+	for _, tt := range tests {
+		got, err := NewMoneyFromFloat(tt.amount, tt.currency, tt.mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != NewMoney(tt.want, tt.currency) {
+			t.Errorf("%v %s: expected %d, got %v", tt.amount, tt.currency, tt.want, got)
+		}
+	}
+
+	_, err := NewMoneyFromFloat(1, "cny", RoundHalfUp)
+	if !errors.Is(err, ErrMoneyInvalidCurrency) {
+		t.Fatalf("expected ErrMoneyInvalidCurrency, got %v", err)
+	}
+
+	_, err = NewMoneyFromFloat(1e30, "CNY", RoundHalfUp)
+	if !errors.Is(err, ErrMoneyOverflow) {
+		t.Fatalf("expected ErrMoneyOverflow, got %v", err)
+	}
+}
+
+func TestMoneyUnmarshalLegacyOrderCreated(t *testing.T) {
+	data := []byte(`{"customer_id":"c1","order_items":[{"ProductId":"p1","Price":12.5,"Number":2}],"order_total":25.01}`)
+
+	var event OrderCreated
+	err := json.Unmarshal(data, &event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if price := event.OrderItems[0].Price; price != NewMoney(1250, DefaultCurrency) {
+		t.Fatalf("expected legacy price 1250 CNY, got %v", price)
+	}
+	if event.OrderTotal != NewMoney(2501, DefaultCurrency) {
+		t.Fatalf("expected legacy order_total 2501 CNY, got %v", event.OrderTotal)
+	}
+
+	data, err = json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reloaded OrderCreated
+	err = json.Unmarshal(data, &reloaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.OrderTotal != event.OrderTotal || reloaded.OrderItems[0].Price != event.OrderItems[0].Price {
+		t.Fatalf("expected round trip to keep amounts, got %+v", reloaded)
+	}
+}
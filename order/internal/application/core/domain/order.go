@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"order/internal/adapters/base"
+	"time"
 )
 
 var (
@@ -11,8 +12,30 @@ var (
 	ErrOrderInvalidState      = errors.New("order state does not allow action")
 	ErrOrderUnhandledEvent    = errors.New("unhandled event in order aggregate")
 	ErrOrderUnhandledSnapshot = errors.New("unhandled snapshot in order aggregate")
+	ErrOrderInvalidRevision   = errors.New("order revision is invalid")
+	ErrOrderNotExpired        = errors.New("order has not expired")
 )
 
+// OrderNotExpiredError 订单尚未到期，ExpiresAt 为订单当前的到期时间，为空时订单不会超时
+type OrderNotExpiredError struct {
+	ExpiresAt *time.Time
+}
+
+func (e *OrderNotExpiredError) Error() string {
+	if e.ExpiresAt == nil {
+		return fmt.Sprintf("%s: order has no deadline", ErrOrderNotExpired)
+	}
+
+	return fmt.Sprintf("%s: order expires at %s", ErrOrderNotExpired, e.ExpiresAt.Format(time.RFC3339))
+}
+
+func (e *OrderNotExpiredError) Unwrap() error {
+	return ErrOrderNotExpired
+}
+
+// ExpiredRejectReason 超时未确认的订单被拒绝时的原因
+const ExpiredRejectReason = "expired"
+
 type OrderState int
 
 const (
@@ -23,6 +46,8 @@ const (
 	CancelPending                // 待取消
 	Cancelled                    // 已取消
 	RevisionPending              // 待修改
+	PartiallyShipped             // 部分发货
+	Shipped                      // 已发货
 )
 
 func (s OrderState) String() string {
@@ -39,6 +64,10 @@ func (s OrderState) String() string {
 		return "Cancelled"
 	case RevisionPending:
 		return "RevisionPending"
+	case PartiallyShipped:
+		return "PartiallyShipped"
+	case Shipped:
+		return "Shipped"
 	default:
 		return "Unknown"
 	}
@@ -48,48 +77,356 @@ var _ base.Aggregate = (*Order)(nil)
 
 type Order struct {
 	base.AggregateBase
-	CustomerID string      `json:"customer_id"`
-	State      OrderState  `json:"status"`
-	OrderItems []OrderItem `json:"order_items"`
+	CustomerID      string         `json:"customer_id"`
+	State           OrderState     `json:"status"`
+	OrderItems      []OrderItem    `json:"order_items"`
+	OrderTotal      Money          `json:"order_total"`
+	Revision        *OrderRevision `json:"revision,omitempty"`
+	DeliveryAddress Address        `json:"delivery_address"`
+	Coupons         []Coupon       `json:"coupons,omitempty"`
+	Discounts       []Discount     `json:"discounts,omitempty"`
+	TaxRates        TaxRates       `json:"tax_rates"`
+	Tax             TaxSummary     `json:"tax"`
+	ExpiresAt       *time.Time     `json:"expires_at,omitempty"`
+	Shipments       []Shipment     `json:"shipments,omitempty"`
+	Returns         []Return       `json:"returns,omitempty"`
+	pricingRules    []PricingRule
 }
 
 type OrderItem struct {
-	ProductId string  `json:"product_id"`
-	Price     float32 `json:"price"`
-	Number    int32   `json:"number"`
+	ProductId string `json:"product_id"`
+	Price     Money  `json:"price"`
+	Number    int32  `json:"number"`
+	TaxClass  string `json:"tax_class,omitempty"`
+	Shipped   int32  `json:"shipped,omitempty"`
+}
+
+func (i OrderItem) GetTotal() (Money, error) {
+	return i.Price.Mul(int64(i.Number))
 }
 
-func NewOrder() base.Aggregate {
-	return &Order{}
+// Outstanding 未发货数量
+func (i OrderItem) Outstanding() int32 {
+	return i.Number - i.Shipped
+}
+
+// OrderItemDelta 订单项数量变化，正数为增加，负数为减少
+type OrderItemDelta struct {
+	ProductId string `json:"product_id"`
+	Delta     int32  `json:"delta"`
+}
+
+// OrderRevision 待确认的修改，确认前原订单项保持不变
+type OrderRevision struct {
+	ItemDeltas       []OrderItemDelta `json:"item_deltas"`
+	RevisedTotal     Money            `json:"revised_total"`
+	RevisedDiscounts []Discount       `json:"revised_discounts,omitempty"`
+	RevisedTax       TaxSummary       `json:"revised_tax"`
+}
+
+type OrderOption func(*Order)
+
+func WithPricingRules(rules ...PricingRule) OrderOption {
+	return func(o *Order) {
+		o.pricingRules = rules
+	}
+}
+
+func NewOrder(options ...OrderOption) base.Aggregate {
+	o := &Order{
+		pricingRules: DefaultPricingRules,
+	}
+
+	for _, option := range options {
+		option(o)
+	}
+
+	return o
 }
 
 func (o *Order) EntityName() string {
 	return "order"
 }
 
+// PersonalDataSubject 订单中的个人数据以客户的密钥加密
+func (o *Order) PersonalDataSubject() string {
+	return o.CustomerID
+}
+
+// Closed 订单已拒绝、已取消，或已全部发货且没有未退款完成的退货，此后通常不再变化
+func (o *Order) Closed() bool {
+	switch o.State {
+	case Rejected, Cancelled:
+		return true
+	case Shipped:
+		for _, r := range o.Returns {
+			if r.Status != ReturnRefunded {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// ClosedOrderEvents 订单结束时最后一个事件可能的名称，用于查找待归档的订单
+func ClosedOrderEvents() []string {
+	return []string{
+		OrderRejected{}.EventName(),
+		OrderCancelled{}.EventName(),
+		OrderShipped{}.EventName(),
+		OrderRefundIssued{}.EventName(),
+	}
+}
+
 func (o *Order) ProcessCommand(command base.Command) error {
+	err := orderStateMachine.CanProcess(o.State, command.CommandName())
+	if err != nil {
+		return err
+	}
+
 	switch cmd := command.(type) {
 	case *CreateOrder:
-		if o.State != UnknownOrderState {
-			return ErrOrderInvalidState
+		err = cmd.Validate()
+		if err != nil {
+			return err
 		}
 
-		var total float32
-		orderItems := make([]CreateOrderItem, 0, len(o.OrderItems))
+		orderItems := make([]OrderItem, 0, len(cmd.OrderItems))
 		for _, orderItem := range cmd.OrderItems {
-			item := CreateOrderItem{
+			orderItems = append(orderItems, OrderItem{
 				ProductId: orderItem.ProductId,
 				Price:     orderItem.Price,
 				Number:    orderItem.Number,
-			}
-			total += item.GetTotal()
-			orderItems = append(orderItems, item)
+				TaxClass:  orderItem.TaxClass,
+			})
+		}
+
+		pricing, tax, err := o.price(cmd.TaxRates, orderItems, nil)
+		if err != nil {
+			return err
 		}
 
 		o.AddEvents(&OrderCreated{
-			CustomerID: cmd.CustomerID,
-			OrderItems: cmd.OrderItems,
-			OrderTotal: total,
+			CustomerID:      cmd.CustomerID,
+			OrderItems:      cmd.OrderItems,
+			OrderTotal:      pricing.Total,
+			Discounts:       pricing.Discounts,
+			DeliveryAddress: cmd.DeliveryAddress,
+			TaxRates:        cmd.TaxRates,
+			Tax:             tax,
+			ExpiresAt:       cmd.ExpiresAt,
+		})
+	case *ApproveOrder:
+		o.AddEvents(&OrderApproved{})
+	case *RejectOrder:
+		o.AddEvents(&OrderRejected{
+			Reason: cmd.Reason,
+		})
+	case *ExpireOrder:
+		if o.ExpiresAt == nil || cmd.Now.Before(*o.ExpiresAt) {
+			return &OrderNotExpiredError{ExpiresAt: o.ExpiresAt}
+		}
+
+		o.AddEvents(&OrderRejected{
+			Reason: ExpiredRejectReason,
+		})
+	case *BeginCancelOrder:
+		o.AddEvents(&OrderCancelBegun{})
+	case *ConfirmCancelOrder:
+		o.AddEvents(&OrderCancelled{})
+	case *UndoCancelOrder:
+		o.AddEvents(&OrderCancelUndone{})
+	case *ReviseOrder:
+		revisedItems, err := o.reviseItems(cmd.ItemDeltas)
+		if err != nil {
+			return err
+		}
+
+		pricing, tax, err := o.price(o.TaxRates, revisedItems, o.Coupons)
+		if err != nil {
+			return err
+		}
+
+		o.AddEvents(&OrderRevisionProposed{
+			ItemDeltas:       cmd.ItemDeltas,
+			CurrentTotal:     o.OrderTotal,
+			RevisedTotal:     pricing.Total,
+			RevisedDiscounts: pricing.Discounts,
+			RevisedTax:       tax,
+		})
+	case *ConfirmRevision:
+		o.AddEvents(&OrderRevised{
+			ItemDeltas: o.Revision.ItemDeltas,
+			OrderTotal: o.Revision.RevisedTotal,
+			Discounts:  o.Revision.RevisedDiscounts,
+			Tax:        o.Revision.RevisedTax,
+		})
+	case *RejectRevision:
+		o.AddEvents(&OrderRevisionRejected{
+			ItemDeltas: o.Revision.ItemDeltas,
+		})
+	case *ChangeDeliveryAddress:
+		err = cmd.Validate()
+		if err != nil {
+			return err
+		}
+
+		_, tax, err := o.price(cmd.TaxRates, o.OrderItems, o.Coupons)
+		if err != nil {
+			return err
+		}
+
+		o.AddEvents(&OrderDeliveryAddressChanged{
+			DeliveryAddress: cmd.DeliveryAddress,
+			TaxRates:        cmd.TaxRates,
+			Tax:             tax,
+		})
+	case *ApplyCoupon:
+		err = cmd.Validate()
+		if err != nil {
+			return err
+		}
+
+		for _, coupon := range o.Coupons {
+			if coupon.Code == cmd.Coupon.Code {
+				return fmt.Errorf("%w: coupon %s already applied", ErrOrderInvalidCoupon, coupon.Code)
+			}
+		}
+
+		if cmd.Coupon.ProductId != "" && !o.hasItem(cmd.Coupon.ProductId) {
+			return fmt.Errorf("%w: product %s is not in the order", ErrOrderInvalidCoupon, cmd.Coupon.ProductId)
+		}
+
+		if cmd.Coupon.Kind == FixedAmountCoupon && cmd.Coupon.Amount.Currency != o.OrderTotal.Currency {
+			return fmt.Errorf("%w: coupon currency %s does not match order currency %s", ErrOrderInvalidCoupon, cmd.Coupon.Amount.Currency, o.OrderTotal.Currency)
+		}
+
+		pricing, tax, err := o.price(o.TaxRates, o.OrderItems, append(o.Coupons[:len(o.Coupons):len(o.Coupons)], cmd.Coupon))
+		if err != nil {
+			return err
+		}
+
+		o.AddEvents(&OrderCouponApplied{
+			Coupon:     cmd.Coupon,
+			Subtotal:   pricing.Subtotal,
+			Discounts:  pricing.Discounts,
+			OrderTotal: pricing.Total,
+			Tax:        tax,
+		})
+	case *RemoveCoupon:
+		coupons := make([]Coupon, 0, len(o.Coupons))
+		for _, coupon := range o.Coupons {
+			if coupon.Code != cmd.Code {
+				coupons = append(coupons, coupon)
+			}
+		}
+
+		if len(coupons) == len(o.Coupons) {
+			return fmt.Errorf("%w: coupon %s is not applied", ErrOrderInvalidCoupon, cmd.Code)
+		}
+
+		pricing, tax, err := o.price(o.TaxRates, o.OrderItems, coupons)
+		if err != nil {
+			return err
+		}
+
+		o.AddEvents(&OrderCouponRemoved{
+			Code:       cmd.Code,
+			Subtotal:   pricing.Subtotal,
+			Discounts:  pricing.Discounts,
+			OrderTotal: pricing.Total,
+			Tax:        tax,
+		})
+	case *RecordShipment:
+		err = cmd.Validate()
+		if err != nil {
+			return err
+		}
+
+		// 根据发货后的剩余数量决定是部分发货还是全部发货
+		shippedItems, err := o.shipItems(cmd.Shipment.Items)
+		if err != nil {
+			return err
+		}
+
+		if fullyShipped(shippedItems) {
+			o.AddEvents(&OrderShipped{Shipment: cmd.Shipment})
+		} else {
+			o.AddEvents(&OrderPartiallyShipped{Shipment: cmd.Shipment})
+		}
+	case *RequestReturn:
+		err = cmd.Validate()
+		if err != nil {
+			return err
+		}
+
+		if _, err = o.findReturn(cmd.ReturnID); err == nil {
+			return fmt.Errorf("%w: return %s already exists", ErrOrderInvalidReturn, cmd.ReturnID)
+		}
+
+		_, err = o.requestReturnItems(cmd.Items)
+		if err != nil {
+			return err
+		}
+
+		o.AddEvents(&OrderReturnRequested{
+			ReturnID:   cmd.ReturnID,
+			ReasonCode: cmd.ReasonCode,
+			Items:      cmd.Items,
+		})
+	case *ReceiveReturn:
+		err = cmd.Validate()
+		if err != nil {
+			return err
+		}
+
+		i, err := o.findReturn(cmd.ReturnID)
+		if err != nil {
+			return err
+		}
+
+		_, err = receiveReturnItems(o.Returns[i], cmd.Items)
+		if err != nil {
+			return err
+		}
+
+		o.AddEvents(&OrderReturnReceived{
+			ReturnID:   cmd.ReturnID,
+			ReasonCode: cmd.ReasonCode,
+			Items:      cmd.Items,
+		})
+	case *IssueRefund:
+		err = cmd.Validate()
+		if err != nil {
+			return err
+		}
+
+		i, err := o.findReturn(cmd.ReturnID)
+		if err != nil {
+			return err
+		}
+
+		refunds, err := o.refundLines(o.Returns[i], cmd.Items)
+		if err != nil {
+			return err
+		}
+
+		total := NewMoney(0, o.OrderTotal.Currency)
+		for _, refund := range refunds {
+			total, err = total.Add(refund.Amount)
+			if err != nil {
+				return err
+			}
+		}
+
+		o.AddEvents(&OrderRefundIssued{
+			ReturnID:   cmd.ReturnID,
+			CustomerID: o.CustomerID,
+			ReasonCode: cmd.ReasonCode,
+			Items:      refunds,
+			Total:      total,
 		})
 	default:
 		return fmt.Errorf("%w: unhandled command %s", ErrOrderUnhandledCommand, command.CommandName())
@@ -99,6 +436,11 @@ func (o *Order) ProcessCommand(command base.Command) error {
 }
 
 func (o *Order) ApplyEvent(event base.Event) error {
+	state, err := orderStateMachine.Transition(o.State, event.EventName())
+	if err != nil {
+		return err
+	}
+
 	switch e := event.(type) {
 	case *OrderCreated:
 		orderItems := make([]OrderItem, 0, len(e.OrderItems))
@@ -107,17 +449,114 @@ func (o *Order) ApplyEvent(event base.Event) error {
 				ProductId: orderItem.ProductId,
 				Price:     orderItem.Price,
 				Number:    orderItem.Number,
+				TaxClass:  orderItem.TaxClass,
 			}
 			orderItems = append(orderItems, orderItem)
 		}
 
 		o.CustomerID = e.CustomerID
 		o.OrderItems = orderItems
-		o.State = ApprovalPending
+		o.OrderTotal = e.OrderTotal
+		o.Discounts = e.Discounts
+		o.DeliveryAddress = e.DeliveryAddress
+		o.TaxRates = e.TaxRates
+		o.Tax = e.Tax
+		o.ExpiresAt = e.ExpiresAt
+	case *OrderApproved, *OrderRejected, *OrderCancelBegun, *OrderCancelled, *OrderCancelUndone:
+		// 仅改变状态
+	case *OrderRevisionProposed:
+		o.Revision = &OrderRevision{
+			ItemDeltas:       e.ItemDeltas,
+			RevisedTotal:     e.RevisedTotal,
+			RevisedDiscounts: e.RevisedDiscounts,
+			RevisedTax:       e.RevisedTax,
+		}
+	case *OrderRevised:
+		revisedItems, err := o.reviseItems(e.ItemDeltas)
+		if err != nil {
+			return err
+		}
+
+		o.OrderItems = revisedItems
+		o.OrderTotal = e.OrderTotal
+		o.Discounts = e.Discounts
+		o.Tax = e.Tax
+		o.Revision = nil
+	case *OrderRevisionRejected:
+		o.Revision = nil
+	case *OrderDeliveryAddressChanged:
+		o.DeliveryAddress = e.DeliveryAddress
+		o.TaxRates = e.TaxRates
+		o.Tax = e.Tax
+	case *OrderCouponApplied:
+		o.Coupons = append(o.Coupons, e.Coupon)
+		o.Discounts = e.Discounts
+		o.OrderTotal = e.OrderTotal
+		o.Tax = e.Tax
+	case *OrderCouponRemoved:
+		coupons := make([]Coupon, 0, len(o.Coupons))
+		for _, coupon := range o.Coupons {
+			if coupon.Code != e.Code {
+				coupons = append(coupons, coupon)
+			}
+		}
+
+		o.Coupons = coupons
+		o.Discounts = e.Discounts
+		o.OrderTotal = e.OrderTotal
+		o.Tax = e.Tax
+	case *OrderPartiallyShipped:
+		err = o.applyShipment(e.Shipment)
+		if err != nil {
+			return err
+		}
+	case *OrderShipped:
+		err = o.applyShipment(e.Shipment)
+		if err != nil {
+			return err
+		}
+	case *OrderReturnRequested:
+		items, err := o.requestReturnItems(e.Items)
+		if err != nil {
+			return err
+		}
+
+		o.Returns = append(o.Returns, Return{
+			ID:         e.ReturnID,
+			ReasonCode: e.ReasonCode,
+			Status:     ReturnRequested,
+			Items:      items,
+		})
+	case *OrderReturnReceived:
+		i, err := o.findReturn(e.ReturnID)
+		if err != nil {
+			return err
+		}
+
+		received, err := receiveReturnItems(o.Returns[i], e.Items)
+		if err != nil {
+			return err
+		}
+
+		o.setReturn(i, received)
+	case *OrderRefundIssued:
+		i, err := o.findReturn(e.ReturnID)
+		if err != nil {
+			return err
+		}
+
+		refunded, err := refundReturnItems(o.Returns[i], e.Items)
+		if err != nil {
+			return err
+		}
+
+		o.setReturn(i, refunded)
 	default:
 		return fmt.Errorf("%w: unhandled event %s", ErrOrderUnhandledEvent, event)
 	}
 
+	o.State = state
+
 	return nil
 }
 
@@ -125,8 +564,18 @@ func (o *Order) ApplySnapshot(snapshot base.Snapshot) error {
 	switch ss := snapshot.(type) {
 	case *OrderSnapshot:
 		o.CustomerID = ss.CustomerID
-		o.State = ApprovalPending
+		o.State = ss.State
 		o.OrderItems = ss.OrderItems
+		o.OrderTotal = ss.OrderTotal
+		o.Revision = ss.Revision
+		o.DeliveryAddress = ss.DeliveryAddress
+		o.Coupons = ss.Coupons
+		o.Discounts = ss.Discounts
+		o.TaxRates = ss.TaxRates
+		o.Tax = ss.Tax
+		o.ExpiresAt = ss.ExpiresAt
+		o.Shipments = ss.Shipments
+		o.Returns = ss.Returns
 	default:
 		return fmt.Errorf("%w: unhandled snapshot %s", ErrOrderUnhandledSnapshot, snapshot)
 	}
@@ -136,21 +585,147 @@ func (o *Order) ApplySnapshot(snapshot base.Snapshot) error {
 
 func (o *Order) ToSnapshot() (base.Snapshot, error) {
 	return &OrderSnapshot{
-		CustomerID: o.CustomerID,
-		OrderItems: o.OrderItems,
-		State:      o.State,
+		CustomerID:      o.CustomerID,
+		OrderItems:      o.OrderItems,
+		OrderTotal:      o.OrderTotal,
+		Revision:        o.Revision,
+		DeliveryAddress: o.DeliveryAddress,
+		Coupons:         o.Coupons,
+		Discounts:       o.Discounts,
+		TaxRates:        o.TaxRates,
+		Tax:             o.Tax,
+		ExpiresAt:       o.ExpiresAt,
+		Shipments:       o.Shipments,
+		Returns:         o.Returns,
+		State:           o.State,
 	}, nil
 }
 
-func (o *Order) GetEvent(eventName string) base.Event {
-	switch eventName {
-	case "OrderCreated":
-		return &OrderCreated{}
+// RegisterOrderTypes 登记订单聚合的事件和快照类型，事件存储加载时据此创建实例
+func RegisterOrderTypes(types *base.TypeRegistry) {
+	base.RegisterEvent[OrderCreated](types)
+	base.RegisterEvent[OrderApproved](types)
+	base.RegisterEvent[OrderRejected](types)
+	base.RegisterEvent[OrderCancelBegun](types)
+	base.RegisterEvent[OrderCancelled](types)
+	base.RegisterEvent[OrderCancelUndone](types)
+	base.RegisterEvent[OrderRevisionProposed](types)
+	base.RegisterEvent[OrderRevised](types)
+	base.RegisterEvent[OrderRevisionRejected](types)
+	base.RegisterEvent[OrderDeliveryAddressChanged](types)
+	base.RegisterEvent[OrderCouponApplied](types)
+	base.RegisterEvent[OrderCouponRemoved](types)
+	base.RegisterEvent[OrderPartiallyShipped](types)
+	base.RegisterEvent[OrderShipped](types)
+	base.RegisterEvent[OrderReturnRequested](types)
+	base.RegisterEvent[OrderReturnReceived](types)
+	base.RegisterEvent[OrderRefundIssued](types)
+
+	base.RegisterSnapshot[OrderSnapshot](types)
+}
+
+// reviseItems 按数量变化生成修改后的订单项，数量减为 0 的订单项会被移除
+func (o *Order) reviseItems(itemDeltas []OrderItemDelta) ([]OrderItem, error) {
+	if len(itemDeltas) == 0 {
+		return nil, fmt.Errorf("%w: no item deltas", ErrOrderInvalidRevision)
+	}
+
+	numbers := make(map[string]int32, len(o.OrderItems))
+	for _, orderItem := range o.OrderItems {
+		numbers[orderItem.ProductId] = orderItem.Number
+	}
+
+	for _, itemDelta := range itemDeltas {
+		number, ok := numbers[itemDelta.ProductId]
+		if !ok {
+			return nil, fmt.Errorf("%w: product %s is not in the order", ErrOrderInvalidRevision, itemDelta.ProductId)
+		}
+
+		numbers[itemDelta.ProductId] = number + itemDelta.Delta
+	}
+
+	revisedItems := make([]OrderItem, 0, len(o.OrderItems))
+	for _, orderItem := range o.OrderItems {
+		number := numbers[orderItem.ProductId]
+		if number < 0 {
+			return nil, fmt.Errorf("%w: product %s quantity would become %d", ErrOrderInvalidRevision, orderItem.ProductId, number)
+		}
+		if number == 0 {
+			continue
+		}
+
+		orderItem.Number = number
+		revisedItems = append(revisedItems, orderItem)
+	}
+
+	if len(revisedItems) == 0 {
+		return nil, fmt.Errorf("%w: revision removes every item", ErrOrderInvalidRevision)
+	}
+
+	return revisedItems, nil
+}
+
+// price 按定价规则计算折扣后，再按税率计算各订单项税额
+func (o *Order) price(rates TaxRates, orderItems []OrderItem, coupons []Coupon) (PriceBreakdown, TaxSummary, error) {
+	pricing, err := priceOrder(o.pricingRules, orderItems, coupons)
+	if err != nil {
+		return PriceBreakdown{}, TaxSummary{}, err
+	}
+
+	tax, err := calculateTax(rates, orderItems, pricing)
+	if err != nil {
+		return PriceBreakdown{}, TaxSummary{}, err
+	}
+
+	return pricing, tax, nil
+}
+
+func (o *Order) applyShipment(shipment Shipment) error {
+	shippedItems, err := o.shipItems(shipment.Items)
+	if err != nil {
+		return err
 	}
 
+	o.OrderItems = shippedItems
+	o.Shipments = append(o.Shipments, shipment)
+
 	return nil
 }
 
-func (o *Order) GetSnapshot() base.Snapshot {
-	return &OrderSnapshot{}
+func (o *Order) hasItem(productId string) bool {
+	_, ok := o.findItem(productId)
+
+	return ok
+}
+
+func (o *Order) findItem(productId string) (OrderItem, bool) {
+	for _, orderItem := range o.OrderItems {
+		if orderItem.ProductId == productId {
+			return orderItem, true
+		}
+	}
+
+	return OrderItem{}, false
+}
+
+func orderTotal(orderItems []OrderItem) (Money, error) {
+	var total Money
+	for i, orderItem := range orderItems {
+		itemTotal, err := orderItem.GetTotal()
+		if err != nil {
+			return Money{}, err
+		}
+
+		if i == 0 {
+			total = itemTotal
+			continue
+		}
+
+		total, err = total.Add(itemTotal)
+		if err != nil {
+			return Money{}, err
+		}
+	}
+
+	return total, nil
 }
@@ -1,20 +1,248 @@
 package domain
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 type CreateOrder struct {
-	CustomerID string
-	OrderItems []CreateOrderItem
+	CustomerID      string
+	OrderItems      []CreateOrderItem
+	DeliveryAddress Address
+	TaxRates        TaxRates
+	ExpiresAt       *time.Time // 为空时订单不会超时
 }
 
 type CreateOrderItem struct {
 	ProductId string
-	Price     float32
+	Price     Money
 	Number    int32
+	TaxClass  string
 }
 
 func (CreateOrder) CommandName() string {
 	return "CreateOrder"
 }
 
-func (i CreateOrderItem) GetTotal() float32 {
-	return i.Price * float32(i.Number)
+func (i CreateOrderItem) GetTotal() (Money, error) {
+	return i.Price.Mul(int64(i.Number))
+}
+
+func (c CreateOrder) Validate() error {
+	violations := &ValidationError{}
+
+	if strings.TrimSpace(c.CustomerID) == "" {
+		violations.Add("customer_id", "must not be empty")
+	}
+
+	if len(c.OrderItems) == 0 {
+		violations.Add("order_items", "must contain at least one item")
+	}
+
+	productIds := make(map[string]int, len(c.OrderItems))
+	for i, orderItem := range c.OrderItems {
+		field := fmt.Sprintf("order_items[%d]", i)
+
+		if strings.TrimSpace(orderItem.ProductId) == "" {
+			violations.Add(field+".product_id", "must not be empty")
+		} else if first, ok := productIds[orderItem.ProductId]; ok {
+			violations.Add(field+".product_id", "duplicates order_items[%d]", first)
+		} else {
+			productIds[orderItem.ProductId] = i
+		}
+
+		if orderItem.Number <= 0 {
+			violations.Add(field+".number", "must be greater than zero")
+		}
+
+		if orderItem.Price.IsNegative() {
+			violations.Add(field+".price", "must not be negative")
+		}
+
+		if !currencyPattern.MatchString(orderItem.Price.Currency) {
+			violations.Add(field+".price.currency", "must be an ISO 4217 currency code")
+		} else if c.OrderItems[0].Price.Currency != orderItem.Price.Currency {
+			violations.Add(field+".price.currency", "must match order_items[0] currency %s", c.OrderItems[0].Price.Currency)
+		}
+	}
+
+	if !c.DeliveryAddress.IsZero() {
+		c.DeliveryAddress.validate("delivery_address", violations)
+	}
+
+	return violations.Err()
+}
+
+type ApproveOrder struct{}
+
+func (ApproveOrder) CommandName() string {
+	return "ApproveOrder"
+}
+
+type RejectOrder struct {
+	Reason string
+}
+
+func (RejectOrder) CommandName() string {
+	return "RejectOrder"
+}
+
+type ExpireOrder struct {
+	Now time.Time
+}
+
+func (ExpireOrder) CommandName() string {
+	return "ExpireOrder"
+}
+
+type BeginCancelOrder struct{}
+
+func (BeginCancelOrder) CommandName() string {
+	return "BeginCancelOrder"
+}
+
+type ConfirmCancelOrder struct{}
+
+func (ConfirmCancelOrder) CommandName() string {
+	return "ConfirmCancelOrder"
+}
+
+type UndoCancelOrder struct{}
+
+func (UndoCancelOrder) CommandName() string {
+	return "UndoCancelOrder"
+}
+
+type ReviseOrder struct {
+	ItemDeltas []OrderItemDelta
+}
+
+func (ReviseOrder) CommandName() string {
+	return "ReviseOrder"
+}
+
+type ConfirmRevision struct{}
+
+func (ConfirmRevision) CommandName() string {
+	return "ConfirmRevision"
+}
+
+type RejectRevision struct{}
+
+func (RejectRevision) CommandName() string {
+	return "RejectRevision"
+}
+
+type ChangeDeliveryAddress struct {
+	DeliveryAddress Address
+	TaxRates        TaxRates
+}
+
+func (ChangeDeliveryAddress) CommandName() string {
+	return "ChangeDeliveryAddress"
+}
+
+func (c ChangeDeliveryAddress) Validate() error {
+	violations := &ValidationError{}
+
+	c.DeliveryAddress.validate("delivery_address", violations)
+
+	return violations.Err()
+}
+
+type ApplyCoupon struct {
+	Coupon Coupon
+}
+
+func (ApplyCoupon) CommandName() string {
+	return "ApplyCoupon"
+}
+
+func (c ApplyCoupon) Validate() error {
+	violations := &ValidationError{}
+
+	c.Coupon.validate("coupon", violations)
+
+	return violations.Err()
+}
+
+type RemoveCoupon struct {
+	Code string
+}
+
+func (RemoveCoupon) CommandName() string {
+	return "RemoveCoupon"
+}
+
+type RecordShipment struct {
+	Shipment Shipment
+}
+
+func (RecordShipment) CommandName() string {
+	return "RecordShipment"
+}
+
+func (c RecordShipment) Validate() error {
+	violations := &ValidationError{}
+
+	c.Shipment.validate("shipment", violations)
+
+	return violations.Err()
+}
+
+type RequestReturn struct {
+	ReturnID   string
+	ReasonCode string
+	Items      []ReturnLine
+}
+
+func (RequestReturn) CommandName() string {
+	return "RequestReturn"
+}
+
+func (c RequestReturn) Validate() error {
+	return validateReturnCommand(c.ReturnID, c.ReasonCode, c.Items)
+}
+
+type ReceiveReturn struct {
+	ReturnID   string
+	ReasonCode string
+	Items      []ReturnLine
+}
+
+func (ReceiveReturn) CommandName() string {
+	return "ReceiveReturn"
+}
+
+func (c ReceiveReturn) Validate() error {
+	return validateReturnCommand(c.ReturnID, c.ReasonCode, c.Items)
+}
+
+type IssueRefund struct {
+	ReturnID   string
+	ReasonCode string
+	Items      []ReturnLine
+}
+
+func (IssueRefund) CommandName() string {
+	return "IssueRefund"
+}
+
+func (c IssueRefund) Validate() error {
+	return validateReturnCommand(c.ReturnID, c.ReasonCode, c.Items)
+}
+
+func validateReturnCommand(returnID string, reasonCode string, items []ReturnLine) error {
+	violations := &ValidationError{}
+
+	if strings.TrimSpace(returnID) == "" {
+		violations.Add("return_id", "must not be empty")
+	}
+	if strings.TrimSpace(reasonCode) == "" {
+		violations.Add("reason_code", "must not be empty")
+	}
+	validateReturnLines(items, violations)
+
+	return violations.Err()
 }
@@ -1,14 +1,160 @@
 package domain
 
-type OrderEvent struct{}
+import (
+	"order/internal/adapters/base"
+	"time"
+)
+
+type OrderEvent struct {
+	base.EventBase
+}
 
 func (OrderEvent) DestinationChannel() string { return "Order" }
 
 type OrderCreated struct {
 	OrderEvent
-	CustomerID string            `json:"customer_id"`
-	OrderItems []CreateOrderItem `json:"order_items"`
-	OrderTotal float32           `json:"order_total"`
+	CustomerID      string            `json:"customer_id" pii:"true"`
+	OrderItems      []CreateOrderItem `json:"order_items"`
+	OrderTotal      Money             `json:"order_total"`
+	Discounts       []Discount        `json:"discounts,omitempty"`
+	DeliveryAddress Address           `json:"delivery_address"`
+	TaxRates        TaxRates          `json:"tax_rates"`
+	Tax             TaxSummary        `json:"tax"`
+	ExpiresAt       *time.Time        `json:"expires_at,omitempty"`
 }
 
 func (OrderCreated) EventName() string { return "OrderCreated" }
+
+type OrderApproved struct {
+	OrderEvent
+}
+
+func (OrderApproved) EventName() string { return "OrderApproved" }
+
+type OrderRejected struct {
+	OrderEvent
+	Reason string `json:"reason"`
+}
+
+func (OrderRejected) EventName() string { return "OrderRejected" }
+
+type OrderCancelBegun struct {
+	OrderEvent
+}
+
+func (OrderCancelBegun) EventName() string { return "OrderCancelBegun" }
+
+type OrderCancelled struct {
+	OrderEvent
+}
+
+func (OrderCancelled) EventName() string { return "OrderCancelled" }
+
+type OrderCancelUndone struct {
+	OrderEvent
+}
+
+func (OrderCancelUndone) EventName() string { return "OrderCancelUndone" }
+
+type OrderRevisionProposed struct {
+	OrderEvent
+	ItemDeltas       []OrderItemDelta `json:"item_deltas"`
+	CurrentTotal     Money            `json:"current_total"`
+	RevisedTotal     Money            `json:"revised_total"`
+	RevisedDiscounts []Discount       `json:"revised_discounts,omitempty"`
+	RevisedTax       TaxSummary       `json:"revised_tax"`
+}
+
+func (OrderRevisionProposed) EventName() string { return "OrderRevisionProposed" }
+
+type OrderRevised struct {
+	OrderEvent
+	ItemDeltas []OrderItemDelta `json:"item_deltas"`
+	OrderTotal Money            `json:"order_total"`
+	Discounts  []Discount       `json:"discounts,omitempty"`
+	Tax        TaxSummary       `json:"tax"`
+}
+
+func (OrderRevised) EventName() string { return "OrderRevised" }
+
+type OrderRevisionRejected struct {
+	OrderEvent
+	ItemDeltas []OrderItemDelta `json:"item_deltas"`
+}
+
+func (OrderRevisionRejected) EventName() string { return "OrderRevisionRejected" }
+
+type OrderDeliveryAddressChanged struct {
+	OrderEvent
+	DeliveryAddress Address    `json:"delivery_address"`
+	TaxRates        TaxRates   `json:"tax_rates"`
+	Tax             TaxSummary `json:"tax"`
+}
+
+func (OrderDeliveryAddressChanged) EventName() string { return "OrderDeliveryAddressChanged" }
+
+type OrderCouponApplied struct {
+	OrderEvent
+	Coupon     Coupon     `json:"coupon"`
+	Subtotal   Money      `json:"subtotal"`
+	Discounts  []Discount `json:"discounts"`
+	OrderTotal Money      `json:"order_total"`
+	Tax        TaxSummary `json:"tax"`
+}
+
+func (OrderCouponApplied) EventName() string { return "OrderCouponApplied" }
+
+type OrderCouponRemoved struct {
+	OrderEvent
+	Code       string     `json:"code"`
+	Subtotal   Money      `json:"subtotal"`
+	Discounts  []Discount `json:"discounts"`
+	OrderTotal Money      `json:"order_total"`
+	Tax        TaxSummary `json:"tax"`
+}
+
+func (OrderCouponRemoved) EventName() string { return "OrderCouponRemoved" }
+
+type OrderPartiallyShipped struct {
+	OrderEvent
+	Shipment Shipment `json:"shipment"`
+}
+
+func (OrderPartiallyShipped) EventName() string { return "OrderPartiallyShipped" }
+
+type OrderShipped struct {
+	OrderEvent
+	Shipment Shipment `json:"shipment"`
+}
+
+func (OrderShipped) EventName() string { return "OrderShipped" }
+
+type OrderReturnRequested struct {
+	OrderEvent
+	ReturnID   string       `json:"return_id"`
+	ReasonCode string       `json:"reason_code"`
+	Items      []ReturnLine `json:"items"`
+}
+
+func (OrderReturnRequested) EventName() string { return "OrderReturnRequested" }
+
+type OrderReturnReceived struct {
+	OrderEvent
+	ReturnID   string       `json:"return_id"`
+	ReasonCode string       `json:"reason_code"`
+	Items      []ReturnLine `json:"items"`
+}
+
+func (OrderReturnReceived) EventName() string { return "OrderReturnReceived" }
+
+// OrderRefundIssued 财务据此向客户退款
+type OrderRefundIssued struct {
+	OrderEvent
+	ReturnID   string       `json:"return_id"`
+	CustomerID string       `json:"customer_id" pii:"true"`
+	ReasonCode string       `json:"reason_code"`
+	Items      []RefundLine `json:"items"`
+	Total      Money        `json:"total"`
+}
+
+func (OrderRefundIssued) EventName() string { return "OrderRefundIssued" }
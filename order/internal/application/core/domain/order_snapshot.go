@@ -1,9 +1,21 @@
 package domain
 
+import "time"
+
 type OrderSnapshot struct {
-	CustomerID string      `json:"customer_id"`
-	State      OrderState  `json:"status"`
-	OrderItems []OrderItem `json:"order_items"`
+	CustomerID      string         `json:"customer_id" pii:"true"`
+	State           OrderState     `json:"status"`
+	OrderItems      []OrderItem    `json:"order_items"`
+	OrderTotal      Money          `json:"order_total"`
+	Revision        *OrderRevision `json:"revision,omitempty"`
+	DeliveryAddress Address        `json:"delivery_address"`
+	Coupons         []Coupon       `json:"coupons,omitempty"`
+	Discounts       []Discount     `json:"discounts,omitempty"`
+	TaxRates        TaxRates       `json:"tax_rates"`
+	Tax             TaxSummary     `json:"tax"`
+	ExpiresAt       *time.Time     `json:"expires_at,omitempty"`
+	Shipments       []Shipment     `json:"shipments,omitempty"`
+	Returns         []Return       `json:"returns,omitempty"`
 }
 
 func (OrderSnapshot) SnapshotName() string { return "OrderSnapshot" }
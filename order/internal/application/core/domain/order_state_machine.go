@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OrderTransition 在 From 状态下处理 Command，产生 Event 并进入 To 状态
+// 同一命令可对应多个事件，由聚合根根据业务数据选择
+type OrderTransition struct {
+	From    OrderState
+	Command string
+	Event   string
+	To      OrderState
+}
+
+var orderStateMachine = NewOrderStateMachine([]OrderTransition{
+	{From: UnknownOrderState, Command: "CreateOrder", Event: "OrderCreated", To: ApprovalPending},
+	{From: ApprovalPending, Command: "ApproveOrder", Event: "OrderApproved", To: Approved},
+	{From: ApprovalPending, Command: "RejectOrder", Event: "OrderRejected", To: Rejected},
+	{From: ApprovalPending, Command: "ExpireOrder", Event: "OrderRejected", To: Rejected},
+	{From: ApprovalPending, Command: "ChangeDeliveryAddress", Event: "OrderDeliveryAddressChanged", To: ApprovalPending},
+	{From: ApprovalPending, Command: "ApplyCoupon", Event: "OrderCouponApplied", To: ApprovalPending},
+	{From: ApprovalPending, Command: "RemoveCoupon", Event: "OrderCouponRemoved", To: ApprovalPending},
+	{From: Approved, Command: "BeginCancelOrder", Event: "OrderCancelBegun", To: CancelPending},
+	{From: CancelPending, Command: "ConfirmCancelOrder", Event: "OrderCancelled", To: Cancelled},
+	{From: CancelPending, Command: "UndoCancelOrder", Event: "OrderCancelUndone", To: Approved},
+	{From: Approved, Command: "ReviseOrder", Event: "OrderRevisionProposed", To: RevisionPending},
+	{From: RevisionPending, Command: "ConfirmRevision", Event: "OrderRevised", To: Approved},
+	{From: RevisionPending, Command: "RejectRevision", Event: "OrderRevisionRejected", To: Approved},
+	{From: Approved, Command: "RecordShipment", Event: "OrderPartiallyShipped", To: PartiallyShipped},
+	{From: Approved, Command: "RecordShipment", Event: "OrderShipped", To: Shipped},
+	{From: PartiallyShipped, Command: "RecordShipment", Event: "OrderPartiallyShipped", To: PartiallyShipped},
+	{From: PartiallyShipped, Command: "RecordShipment", Event: "OrderShipped", To: Shipped},
+	{From: PartiallyShipped, Command: "RequestReturn", Event: "OrderReturnRequested", To: PartiallyShipped},
+	{From: PartiallyShipped, Command: "ReceiveReturn", Event: "OrderReturnReceived", To: PartiallyShipped},
+	{From: PartiallyShipped, Command: "IssueRefund", Event: "OrderRefundIssued", To: PartiallyShipped},
+	{From: Shipped, Command: "RequestReturn", Event: "OrderReturnRequested", To: Shipped},
+	{From: Shipped, Command: "ReceiveReturn", Event: "OrderReturnReceived", To: Shipped},
+	{From: Shipped, Command: "IssueRefund", Event: "OrderRefundIssued", To: Shipped},
+})
+
+// OrderTransitionError 记录被拒绝的状态转换，可通过 errors.Is 匹配 ErrOrderInvalidState
+type OrderTransitionError struct {
+	State   OrderState
+	Command string
+	Event   string
+}
+
+func (e *OrderTransitionError) Error() string {
+	if e.Command != "" {
+		return fmt.Sprintf("%s: command %s in state %s", ErrOrderInvalidState, e.Command, e.State)
+	}
+
+	return fmt.Sprintf("%s: event %s in state %s", ErrOrderInvalidState, e.Event, e.State)
+}
+
+func (e *OrderTransitionError) Unwrap() error {
+	return ErrOrderInvalidState
+}
+
+type orderTransitionKey struct {
+	state OrderState
+	name  string
+}
+
+type OrderStateMachine struct {
+	transitions []OrderTransition
+	commands    map[orderTransitionKey]bool
+	events      map[orderTransitionKey]OrderState
+	known       map[string]bool
+}
+
+func NewOrderStateMachine(transitions []OrderTransition) *OrderStateMachine {
+	m := &OrderStateMachine{
+		transitions: transitions,
+		commands:    make(map[orderTransitionKey]bool, len(transitions)),
+		events:      make(map[orderTransitionKey]OrderState, len(transitions)),
+		known:       make(map[string]bool, len(transitions)*2),
+	}
+
+	for _, transition := range transitions {
+		eventKey := orderTransitionKey{state: transition.From, name: transition.Event}
+		if to, ok := m.events[eventKey]; ok && to != transition.To {
+			panic(fmt.Sprintf("conflicting order transition: event %s in state %s", transition.Event, transition.From))
+		}
+
+		m.commands[orderTransitionKey{state: transition.From, name: transition.Command}] = true
+		m.events[eventKey] = transition.To
+		m.known[transition.Command] = true
+		m.known[transition.Event] = true
+	}
+
+	return m
+}
+
+// OrderMachine 返回订单状态机，用于导出状态图
+func OrderMachine() *OrderStateMachine {
+	return orderStateMachine
+}
+
+func (m *OrderStateMachine) Transitions() []OrderTransition {
+	return append([]OrderTransition(nil), m.transitions...)
+}
+
+// CanProcess 检查当前状态是否允许处理命令
+func (m *OrderStateMachine) CanProcess(state OrderState, command string) error {
+	if m.commands[orderTransitionKey{state: state, name: command}] {
+		return nil
+	}
+
+	if !m.known[command] {
+		return fmt.Errorf("%w: unhandled command %s", ErrOrderUnhandledCommand, command)
+	}
+
+	return &OrderTransitionError{State: state, Command: command}
+}
+
+// Transition 返回应用事件后的状态
+func (m *OrderStateMachine) Transition(state OrderState, event string) (OrderState, error) {
+	if to, ok := m.events[orderTransitionKey{state: state, name: event}]; ok {
+		return to, nil
+	}
+
+	if !m.known[event] {
+		return state, fmt.Errorf("%w: unhandled event %s", ErrOrderUnhandledEvent, event)
+	}
+
+	return state, &OrderTransitionError{State: state, Event: event}
+}
+
+func (m *OrderStateMachine) DOT() string {
+	var b strings.Builder
+
+	b.WriteString("digraph Order {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=rounded];\n")
+	fmt.Fprintf(&b, "  %q [shape=point];\n", UnknownOrderState.String())
+	for _, state := range m.terminalStates() {
+		fmt.Fprintf(&b, "  %q [peripheries=2];\n", state.String())
+	}
+	for _, transition := range m.transitions {
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", transition.From.String(), transition.To.String(), transition.Command+" / "+transition.Event)
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+func (m *OrderStateMachine) Mermaid() string {
+	var b strings.Builder
+
+	b.WriteString("stateDiagram-v2\n")
+	for _, transition := range m.transitions {
+		fmt.Fprintf(&b, "    %s --> %s: %s / %s\n", mermaidState(transition.From), mermaidState(transition.To), transition.Command, transition.Event)
+	}
+	for _, state := range m.terminalStates() {
+		fmt.Fprintf(&b, "    %s --> [*]\n", state)
+	}
+
+	return b.String()
+}
+
+// terminalStates 没有出边的状态
+func (m *OrderStateMachine) terminalStates() []OrderState {
+	outgoing := make(map[OrderState]bool)
+	for _, transition := range m.transitions {
+		if transition.From != transition.To {
+			outgoing[transition.From] = true
+		}
+	}
+
+	seen := make(map[OrderState]bool)
+	var states []OrderState
+	for _, transition := range m.transitions {
+		if !outgoing[transition.To] && !seen[transition.To] {
+			seen[transition.To] = true
+			states = append(states, transition.To)
+		}
+	}
+
+	sort.Slice(states, func(i, j int) bool { return states[i] < states[j] })
+
+	return states
+}
+
+func mermaidState(state OrderState) string {
+	if state == UnknownOrderState {
+		return "[*]"
+	}
+
+	return state.String()
+}
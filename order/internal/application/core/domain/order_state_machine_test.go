@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestOrderStateMachineTransitions(t *testing.T) {
+	machine := OrderMachine()
+
+	for _, transition := range machine.Transitions() {
+		err := machine.CanProcess(transition.From, transition.Command)
+		if err != nil {
+			t.Errorf("%s in %s: %v", transition.Command, transition.From, err)
+		}
+
+		to, err := machine.Transition(transition.From, transition.Event)
+		if err != nil || to != transition.To {
+			t.Errorf("%s in %s: expected %s, got %s %v", transition.Event, transition.From, transition.To, to, err)
+		}
+	}
+}
+
+func TestOrderStateMachineInvalidTransitions(t *testing.T) {
+	machine := OrderMachine()
+
+	states := make(map[OrderState]bool)
+	commands := make(map[string]bool)
+	events := make(map[string]bool)
+	allowedCommands := make(map[OrderState]map[string]bool)
+	allowedEvents := make(map[OrderState]map[string]bool)
+	for _, transition := range machine.Transitions() {
+		states[transition.From] = true
+		states[transition.To] = true
+		commands[transition.Command] = true
+		events[transition.Event] = true
+
+		if allowedCommands[transition.From] == nil {
+			allowedCommands[transition.From] = make(map[string]bool)
+			allowedEvents[transition.From] = make(map[string]bool)
+		}
+		allowedCommands[transition.From][transition.Command] = true
+		allowedEvents[transition.From][transition.Event] = true
+	}
+
+	for state := range states {
+		invalid := 0
+
+		for command := range commands {
+			if allowedCommands[state][command] {
+				continue
+			}
+			invalid++
+
+			err := machine.CanProcess(state, command)
+
+			var transitionErr *OrderTransitionError
+			if !errors.As(err, &transitionErr) || !errors.Is(err, ErrOrderInvalidState) {
+				t.Fatalf("%s in %s: expected OrderTransitionError, got %v", command, state, err)
+			}
+			if transitionErr.State != state || transitionErr.Command != command {
+				t.Fatalf("%s in %s: unexpected error %+v", command, state, transitionErr)
+			}
+		}
+
+		for event := range events {
+			if allowedEvents[state][event] {
+				continue
+			}
+
+			to, err := machine.Transition(state, event)
+
+			var transitionErr *OrderTransitionError
+			if !errors.As(err, &transitionErr) || transitionErr.Event != event || to != state {
+				t.Fatalf("%s in %s: expected OrderTransitionError and unchanged state, got %s %v", event, state, to, err)
+			}
+		}
+
+		if invalid == 0 {
+			t.Errorf("expected at least one invalid command in state %s", state)
+		}
+	}
+}
+
+func TestOrderStateMachineUnknownNames(t *testing.T) {
+	machine := OrderMachine()
+
+	err := machine.CanProcess(ApprovalPending, "ShipToMoon")
+	if !errors.Is(err, ErrOrderUnhandledCommand) {
+		t.Fatalf("expected ErrOrderUnhandledCommand, got %v", err)
+	}
+
+	_, err = machine.Transition(ApprovalPending, "OrderLaunched")
+	if !errors.Is(err, ErrOrderUnhandledEvent) {
+		t.Fatalf("expected ErrOrderUnhandledEvent, got %v", err)
+	}
+}
+
+func TestOrderStateMachineConflictingTransition(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic on conflicting transition")
+		}
+	}()
+
+	NewOrderStateMachine([]OrderTransition{
+		{From: ApprovalPending, Command: "ApproveOrder", Event: "OrderApproved", To: Approved},
+		{From: ApprovalPending, Command: "RejectOrder", Event: "OrderApproved", To: Rejected},
+	})
+}
+
+func testStateMachine() *OrderStateMachine {
+	return NewOrderStateMachine([]OrderTransition{
+		{From: UnknownOrderState, Command: "CreateOrder", Event: "OrderCreated", To: ApprovalPending},
+		{From: ApprovalPending, Command: "ApproveOrder", Event: "OrderApproved", To: Approved},
+		{From: ApprovalPending, Command: "RejectOrder", Event: "OrderRejected", To: Rejected},
+	})
+}
+
+func TestOrderStateMachineDOT(t *testing.T) {
+	want := `digraph Order {
+  rankdir=LR;
+  node [shape=box, style=rounded];
+  "Unknown" [shape=point];
+  "Approved" [peripheries=2];
+  "Rejected" [peripheries=2];
+  "Unknown" -> "ApprovalPending" [label="CreateOrder / OrderCreated"];
+  "ApprovalPending" -> "Approved" [label="ApproveOrder / OrderApproved"];
+  "ApprovalPending" -> "Rejected" [label="RejectOrder / OrderRejected"];
+}
+`
+	if got := testStateMachine().DOT(); got != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, got)
+	}
+
+	dot := OrderMachine().DOT()
+	for _, transition := range OrderMachine().Transitions() {
+		if !strings.Contains(dot, transition.Command+" / "+transition.Event) {
+			t.Errorf("expected DOT to contain %s / %s", transition.Command, transition.Event)
+		}
+	}
+}
+
+func TestOrderStateMachineMermaid(t *testing.T) {
+	want := `stateDiagram-v2
+    [*] --> ApprovalPending: CreateOrder / OrderCreated
+    ApprovalPending --> Approved: ApproveOrder / OrderApproved
+    ApprovalPending --> Rejected: RejectOrder / OrderRejected
+    Approved --> [*]
+    Rejected --> [*]
+`
+	if got := testStateMachine().Mermaid(); got != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestOrderRejectsInvalidCommand(t *testing.T) {
+	o := NewOrder().(*Order)
+
+	err := testHandle(t, o, &CreateOrder{
+		CustomerID: "c1",
+		OrderItems: []CreateOrderItem{{ProductId: "A", Price: cny(1000), Number: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = testHandle(t, o, &ApproveOrder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = testHandle(t, o, &RejectOrder{Reason: "too late"})
+
+	var transitionErr *OrderTransitionError
+	if !errors.As(err, &transitionErr) || transitionErr.State != Approved || transitionErr.Command != "RejectOrder" {
+		t.Fatalf("expected OrderTransitionError for RejectOrder in Approved, got %v", err)
+	}
+	if o.State != Approved {
+		t.Fatalf("expected order to stay Approved, got %s", o.State)
+	}
+}
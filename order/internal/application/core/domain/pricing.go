@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrOrderInvalidCoupon = errors.New("order coupon is invalid")
+
+// DefaultPricingRules 未指定定价规则时使用，仅计算优惠券折扣
+var DefaultPricingRules = []PricingRule{CouponPricingRule{}}
+
+type CouponKind int
+
+const (
+	UnknownCouponKind CouponKind = iota
+	PercentageCoupon             // 按比例折扣
+	FixedAmountCoupon            // 固定金额折扣
+)
+
+func (k CouponKind) String() string {
+	switch k {
+	case PercentageCoupon:
+		return "Percentage"
+	case FixedAmountCoupon:
+		return "FixedAmount"
+	default:
+		return "Unknown"
+	}
+}
+
+// Coupon 优惠券，ProductId 为空时作用于整单，否则作用于对应订单项
+type Coupon struct {
+	Code        string     `json:"code"`
+	Kind        CouponKind `json:"kind"`
+	BasisPoints int64      `json:"basis_points,omitempty"` // 按比例折扣的万分比，1000 即 10%
+	Amount      Money      `json:"amount"`                 // 固定金额折扣，作用于订单项时按整行计算
+	ProductId   string     `json:"product_id,omitempty"`
+}
+
+func (c Coupon) validate(field string, violations *ValidationError) {
+	if strings.TrimSpace(c.Code) == "" {
+		violations.Add(field+".code", "must not be empty")
+	}
+
+	switch c.Kind {
+	case PercentageCoupon:
+		if c.BasisPoints <= 0 || c.BasisPoints > 10000 {
+			violations.Add(field+".basis_points", "must be between 1 and 10000")
+		}
+	case FixedAmountCoupon:
+		if c.Amount.Amount <= 0 {
+			violations.Add(field+".amount", "must be greater than zero")
+		}
+		if !currencyPattern.MatchString(c.Amount.Currency) {
+			violations.Add(field+".amount.currency", "must be an ISO 4217 currency code")
+		}
+	default:
+		violations.Add(field+".kind", "must be Percentage or FixedAmount")
+	}
+}
+
+// Discount 折扣明细，ProductId 为空时为整单折扣
+type Discount struct {
+	Source    string `json:"source"`
+	ProductId string `json:"product_id,omitempty"`
+	Amount    Money  `json:"amount"`
+}
+
+// PriceBreakdown 订单金额明细，Total 等于 Subtotal 减去全部折扣
+type PriceBreakdown struct {
+	Subtotal  Money      `json:"subtotal"`
+	Discounts []Discount `json:"discounts"`
+	Total     Money      `json:"total"`
+}
+
+type PricingInput struct {
+	OrderItems []OrderItem
+	Coupons    []Coupon
+	Subtotal   Money
+}
+
+// PricingRule 计算订单金额时依次调用，返回的折扣会被截断到订单项或整单的剩余金额
+type PricingRule interface {
+	Discounts(input PricingInput) ([]Discount, error)
+}
+
+type CouponPricingRule struct{}
+
+func (CouponPricingRule) Discounts(input PricingInput) ([]Discount, error) {
+	lineTotals := make(map[string]Money, len(input.OrderItems))
+	for _, orderItem := range input.OrderItems {
+		lineTotal, err := orderItem.GetTotal()
+		if err != nil {
+			return nil, err
+		}
+
+		lineTotals[orderItem.ProductId] = lineTotal
+	}
+
+	discounts := make([]Discount, 0, len(input.Coupons))
+	for _, coupon := range input.Coupons {
+		base := input.Subtotal
+		if coupon.ProductId != "" {
+			lineTotal, ok := lineTotals[coupon.ProductId]
+			if !ok {
+				// 订单项已被修改移除，优惠券不再生效
+				continue
+			}
+			base = lineTotal
+		}
+
+		amount := coupon.Amount
+		if coupon.Kind == PercentageCoupon {
+			var err error
+			amount, err = base.MulRatio(coupon.BasisPoints, 10000, RoundHalfUp)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		discounts = append(discounts, Discount{
+			Source:    coupon.Code,
+			ProductId: coupon.ProductId,
+			Amount:    amount,
+		})
+	}
+
+	return discounts, nil
+}
+
+func priceOrder(rules []PricingRule, orderItems []OrderItem, coupons []Coupon) (PriceBreakdown, error) {
+	subtotal, err := orderTotal(orderItems)
+	if err != nil {
+		return PriceBreakdown{}, err
+	}
+
+	lineRemaining := make(map[string]Money, len(orderItems))
+	for _, orderItem := range orderItems {
+		lineRemaining[orderItem.ProductId], err = orderItem.GetTotal()
+		if err != nil {
+			return PriceBreakdown{}, err
+		}
+	}
+
+	input := PricingInput{
+		OrderItems: orderItems,
+		Coupons:    coupons,
+		Subtotal:   subtotal,
+	}
+
+	remaining := subtotal
+	var discounts []Discount
+	for _, rule := range rules {
+		ruleDiscounts, err := rule.Discounts(input)
+		if err != nil {
+			return PriceBreakdown{}, err
+		}
+
+		for _, discount := range ruleDiscounts {
+			if discount.Amount.IsNegative() {
+				return PriceBreakdown{}, fmt.Errorf("%w: discount %s is negative", ErrOrderInvalidCoupon, discount.Source)
+			}
+
+			amount := discount.Amount
+			if discount.ProductId != "" {
+				lineTotal, ok := lineRemaining[discount.ProductId]
+				if !ok {
+					return PriceBreakdown{}, fmt.Errorf("%w: discount %s for unknown product %s", ErrOrderInvalidCoupon, discount.Source, discount.ProductId)
+				}
+
+				amount, err = minMoney(amount, lineTotal)
+				if err != nil {
+					return PriceBreakdown{}, err
+				}
+
+				lineRemaining[discount.ProductId], err = lineTotal.Sub(amount)
+				if err != nil {
+					return PriceBreakdown{}, err
+				}
+			}
+
+			amount, err = minMoney(amount, remaining)
+			if err != nil {
+				return PriceBreakdown{}, err
+			}
+
+			if amount.IsZero() {
+				continue
+			}
+
+			remaining, err = remaining.Sub(amount)
+			if err != nil {
+				return PriceBreakdown{}, err
+			}
+
+			discount.Amount = amount
+			discounts = append(discounts, discount)
+		}
+	}
+
+	return PriceBreakdown{
+		Subtotal:  subtotal,
+		Discounts: discounts,
+		Total:     remaining,
+	}, nil
+}
+
+func minMoney(a, b Money) (Money, error) {
+	diff, err := a.Sub(b)
+	if err != nil {
+		return Money{}, err
+	}
+
+	if diff.IsNegative() {
+		return a, nil
+	}
+
+	return b, nil
+}
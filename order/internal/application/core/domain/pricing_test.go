@@ -0,0 +1,245 @@
+package domain
+
+import (
+	"errors"
+	"order/internal/adapters/base"
+	"testing"
+)
+
+type testPricingRule func(input PricingInput) ([]Discount, error)
+
+func (r testPricingRule) Discounts(input PricingInput) ([]Discount, error) {
+	return r(input)
+}
+
+// testHandle 处理命令并立即应用产生的事件
+func testHandle(t *testing.T, o *Order, command base.Command) error {
+	t.Helper()
+
+	err := o.ProcessCommand(command)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range o.Events() {
+		err = o.ApplyEvent(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	o.ClearEvents()
+
+	return nil
+}
+
+func cny(amount int64) Money {
+	return NewMoney(amount, "CNY")
+}
+
+func TestPriceOrder(t *testing.T) {
+	// 小计 2500：A 为 1000 x 2，B 为 500 x 1
+	orderItems := []OrderItem{
+		{ProductId: "A", Price: cny(1000), Number: 2},
+		{ProductId: "B", Price: cny(500), Number: 1},
+	}
+
+	fixed := func(code string, amount Money, productId string) Coupon {
+		return Coupon{Code: code, Kind: FixedAmountCoupon, Amount: amount, ProductId: productId}
+	}
+	percentage := func(code string, basisPoints int64, productId string) Coupon {
+		return Coupon{Code: code, Kind: PercentageCoupon, BasisPoints: basisPoints, ProductId: productId}
+	}
+
+	tests := []struct {
+		name       string
+		orderItems []OrderItem
+		coupons    []Coupon
+		discounts  []Discount
+		total      int64
+		err        error
+	}{
+		{
+			name:  "no coupons",
+			total: 2500,
+		},
+		{
+			name:      "fixed order coupon",
+			coupons:   []Coupon{fixed("F300", cny(300), "")},
+			discounts: []Discount{{Source: "F300", Amount: cny(300)}},
+			total:     2200,
+		},
+		{
+			name:      "percentage order coupon",
+			coupons:   []Coupon{percentage("P10", 1000, "")},
+			discounts: []Discount{{Source: "P10", Amount: cny(250)}},
+			total:     2250,
+		},
+		{
+			name:      "percentage line coupon",
+			coupons:   []Coupon{percentage("P15A", 1500, "A")},
+			discounts: []Discount{{Source: "P15A", ProductId: "A", Amount: cny(300)}},
+			total:     2200,
+		},
+		{
+			name:       "percentage rounds half up",
+			orderItems: []OrderItem{{ProductId: "C", Price: cny(5), Number: 1}},
+			coupons:    []Coupon{percentage("P10", 1000, "")},
+			discounts:  []Discount{{Source: "P10", Amount: cny(1)}},
+			total:      4,
+		},
+		{
+			name:      "fixed line coupon capped at line total",
+			coupons:   []Coupon{fixed("F800B", cny(800), "B")},
+			discounts: []Discount{{Source: "F800B", ProductId: "B", Amount: cny(500)}},
+			total:     2000,
+		},
+		{
+			name:      "fixed order coupon capped at order total",
+			coupons:   []Coupon{fixed("F3000", cny(3000), "")},
+			discounts: []Discount{{Source: "F3000", Amount: cny(2500)}},
+			total:     0,
+		},
+		{
+			name:    "coupons stack on the subtotal",
+			coupons: []Coupon{percentage("P10", 1000, ""), fixed("F300", cny(300), "")},
+			discounts: []Discount{
+				{Source: "P10", Amount: cny(250)},
+				{Source: "F300", Amount: cny(300)},
+			},
+			total: 1950,
+		},
+		{
+			name:    "stacked line coupons share the line total",
+			coupons: []Coupon{fixed("F400B", cny(400), "B"), percentage("P50B", 5000, "B")},
+			discounts: []Discount{
+				{Source: "F400B", ProductId: "B", Amount: cny(400)},
+				{Source: "P50B", ProductId: "B", Amount: cny(100)},
+			},
+			total: 2000,
+		},
+		{
+			name:    "order coupon capped after line coupons",
+			coupons: []Coupon{fixed("F2000A", cny(2000), "A"), fixed("F1000", cny(1000), ""), percentage("P10", 1000, "")},
+			discounts: []Discount{
+				{Source: "F2000A", ProductId: "A", Amount: cny(2000)},
+				{Source: "F1000", Amount: cny(500)},
+			},
+			total: 0,
+		},
+		{
+			name:    "coupon for removed product is skipped",
+			coupons: []Coupon{fixed("F100C", cny(100), "C")},
+			total:   2500,
+		},
+		{
+			name:    "coupon currency mismatch",
+			coupons: []Coupon{fixed("F100", NewMoney(100, "USD"), "")},
+			err:     ErrMoneyCurrencyMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := tt.orderItems
+			if items == nil {
+				items = orderItems
+			}
+
+			pricing, err := priceOrder(DefaultPricingRules, items, tt.coupons)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if tt.err != nil {
+				return
+			}
+
+			if pricing.Total != cny(tt.total) {
+				t.Fatalf("expected total %d, got %v", tt.total, pricing.Total)
+			}
+			if len(pricing.Discounts) != len(tt.discounts) {
+				t.Fatalf("expected discounts %v, got %v", tt.discounts, pricing.Discounts)
+			}
+			for i, discount := range tt.discounts {
+				if pricing.Discounts[i] != discount {
+					t.Errorf("discount %d: expected %v, got %v", i, discount, pricing.Discounts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPriceOrderRules(t *testing.T) {
+	orderItems := []OrderItem{{ProductId: "A", Price: cny(1000), Number: 1}}
+
+	tests := []struct {
+		name string
+		rule testPricingRule
+		err  error
+	}{
+		{
+			name: "negative discount",
+			rule: func(PricingInput) ([]Discount, error) {
+				return []Discount{{Source: "bad", Amount: cny(-1)}}, nil
+			},
+			err: ErrOrderInvalidCoupon,
+		},
+		{
+			name: "unknown product",
+			rule: func(PricingInput) ([]Discount, error) {
+				return []Discount{{Source: "bad", ProductId: "Z", Amount: cny(1)}}, nil
+			},
+			err: ErrOrderInvalidCoupon,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := priceOrder([]PricingRule{tt.rule}, orderItems, nil)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	// 多个规则依次叠加，后续规则同样受剩余金额限制
+	member := testPricingRule(func(input PricingInput) ([]Discount, error) {
+		return []Discount{{Source: "member", Amount: cny(900)}}, nil
+	})
+	pricing, err := priceOrder([]PricingRule{member, CouponPricingRule{}}, orderItems, []Coupon{{Code: "P50", Kind: PercentageCoupon, BasisPoints: 5000}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pricing.Total != cny(0) || len(pricing.Discounts) != 2 || pricing.Discounts[1].Amount != cny(100) {
+		t.Fatalf("expected member then capped coupon discount, got %+v", pricing)
+	}
+}
+
+func TestApplyCouponCurrencyMismatch(t *testing.T) {
+	o := NewOrder().(*Order)
+
+	err := testHandle(t, o, &CreateOrder{
+		CustomerID: "c1",
+		OrderItems: []CreateOrderItem{{ProductId: "A", Price: cny(1000), Number: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = testHandle(t, o, &ApplyCoupon{Coupon: Coupon{Code: "F100", Kind: FixedAmountCoupon, Amount: NewMoney(100, "USD")}})
+	if !errors.Is(err, ErrOrderInvalidCoupon) {
+		t.Fatalf("expected ErrOrderInvalidCoupon, got %v", err)
+	}
+
+	err = testHandle(t, o, &ApplyCoupon{Coupon: Coupon{Code: "F100", Kind: FixedAmountCoupon, Amount: cny(100)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.OrderTotal != cny(900) {
+		t.Fatalf("expected order total 900, got %v", o.OrderTotal)
+	}
+
+	err = testHandle(t, o, &ApplyCoupon{Coupon: Coupon{Code: "F100", Kind: FixedAmountCoupon, Amount: cny(100)}})
+	if !errors.Is(err, ErrOrderInvalidCoupon) {
+		t.Fatalf("expected ErrOrderInvalidCoupon for duplicate coupon, got %v", err)
+	}
+}
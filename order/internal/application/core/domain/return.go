@@ -0,0 +1,300 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrOrderInvalidReturn = errors.New("order return is invalid")
+
+type ReturnStatus int
+
+const (
+	UnknownReturnStatus ReturnStatus = iota
+	ReturnRequested                  // 已申请退货
+	ReturnReceived                   // 退货已全部入库
+	ReturnRefunded                   // 已全部退款
+)
+
+func (s ReturnStatus) String() string {
+	switch s {
+	case ReturnRequested:
+		return "Requested"
+	case ReturnReceived:
+		return "Received"
+	case ReturnRefunded:
+		return "Refunded"
+	default:
+		return "Unknown"
+	}
+}
+
+// Return 退货单，记录各订单项的申请、入库和退款数量
+type Return struct {
+	ID         string       `json:"id"`
+	ReasonCode string       `json:"reason_code"`
+	Status     ReturnStatus `json:"status"`
+	Items      []ReturnItem `json:"items"`
+}
+
+type ReturnItem struct {
+	ProductId      string `json:"product_id"`
+	Requested      int32  `json:"requested"`
+	Received       int32  `json:"received,omitempty"`
+	Refunded       int32  `json:"refunded,omitempty"`
+	RefundedAmount Money  `json:"refunded_amount"`
+}
+
+// ReturnLine 退货命令和事件中的订单项数量
+type ReturnLine struct {
+	ProductId string `json:"product_id"`
+	Number    int32  `json:"number"`
+}
+
+// RefundLine 订单项退款金额，Amount 按实付金额（含税、扣除折扣）分摊
+type RefundLine struct {
+	ProductId string `json:"product_id"`
+	Number    int32  `json:"number"`
+	Amount    Money  `json:"amount"`
+}
+
+func validateReturnLines(lines []ReturnLine, violations *ValidationError) {
+	if len(lines) == 0 {
+		violations.Add("items", "must not be empty")
+	}
+
+	seen := make(map[string]int, len(lines))
+	for i, line := range lines {
+		field := fmt.Sprintf("items[%d]", i)
+		if strings.TrimSpace(line.ProductId) == "" {
+			violations.Add(field+".product_id", "must not be empty")
+		} else if first, ok := seen[line.ProductId]; ok {
+			violations.Add(field+".product_id", "duplicates items[%d]", first)
+		} else {
+			seen[line.ProductId] = i
+		}
+
+		if line.Number <= 0 {
+			violations.Add(field+".number", "must be greater than zero")
+		}
+	}
+}
+
+func (o *Order) findReturn(returnID string) (int, error) {
+	for i, r := range o.Returns {
+		if r.ID == returnID {
+			return i, nil
+		}
+	}
+
+	return -1, fmt.Errorf("%w: return %s not found", ErrOrderInvalidReturn, returnID)
+}
+
+// setReturn 替换退货单，不修改快照中共享的切片
+func (o *Order) setReturn(i int, r Return) {
+	returns := append([]Return(nil), o.Returns...)
+	returns[i] = r
+	o.Returns = returns
+}
+
+// requestReturnItems 申请数量不能超过已发货且未申请退货的数量
+func (o *Order) requestReturnItems(lines []ReturnLine) ([]ReturnItem, error) {
+	requested := make(map[string]int32)
+	for _, r := range o.Returns {
+		for _, item := range r.Items {
+			requested[item.ProductId] += item.Requested
+		}
+	}
+
+	items := make([]ReturnItem, 0, len(lines))
+	for _, line := range lines {
+		orderItem, ok := o.findItem(line.ProductId)
+		if !ok {
+			return nil, fmt.Errorf("%w: product %s is not in the order", ErrOrderInvalidReturn, line.ProductId)
+		}
+
+		returnable := orderItem.Shipped - requested[line.ProductId]
+		if line.Number > returnable {
+			return nil, fmt.Errorf("%w: product %s returns %d but only %d returnable", ErrOrderInvalidReturn, line.ProductId, line.Number, returnable)
+		}
+
+		items = append(items, ReturnItem{
+			ProductId: line.ProductId,
+			Requested: line.Number,
+		})
+	}
+
+	return items, nil
+}
+
+// receiveReturnItems 入库数量不能超过申请后尚未入库的数量
+func receiveReturnItems(r Return, lines []ReturnLine) (Return, error) {
+	items := append([]ReturnItem(nil), r.Items...)
+	for _, line := range lines {
+		i := returnItemIndex(items, line.ProductId)
+		if i < 0 {
+			return Return{}, fmt.Errorf("%w: product %s is not in return %s", ErrOrderInvalidReturn, line.ProductId, r.ID)
+		}
+
+		outstanding := items[i].Requested - items[i].Received
+		if line.Number > outstanding {
+			return Return{}, fmt.Errorf("%w: product %s receives %d but only %d outstanding", ErrOrderInvalidReturn, line.ProductId, line.Number, outstanding)
+		}
+
+		items[i].Received += line.Number
+	}
+
+	r.Items = items
+	r.Status = returnStatus(items)
+
+	return r, nil
+}
+
+// refundLines 按实付金额计算退款，累计退款按数量比例向下取整，全部退完时恰好等于实付金额
+func (o *Order) refundLines(r Return, lines []ReturnLine) ([]RefundLine, error) {
+	refunds := make([]RefundLine, 0, len(lines))
+	for _, line := range lines {
+		i := returnItemIndex(r.Items, line.ProductId)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: product %s is not in return %s", ErrOrderInvalidReturn, line.ProductId, r.ID)
+		}
+
+		refundable := r.Items[i].Received - r.Items[i].Refunded
+		if line.Number > refundable {
+			return nil, fmt.Errorf("%w: product %s refunds %d but only %d received and not refunded", ErrOrderInvalidReturn, line.ProductId, line.Number, refundable)
+		}
+
+		orderItem, _ := o.findItem(line.ProductId)
+		paid, err := o.paidAmount(orderItem)
+		if err != nil {
+			return nil, err
+		}
+
+		refundedNumber, refundedAmount, err := o.refunded(line.ProductId, paid.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		before, err := paid.MulRatio(int64(refundedNumber), int64(orderItem.Number), RoundDown)
+		if err != nil {
+			return nil, err
+		}
+
+		after, err := paid.MulRatio(int64(refundedNumber+line.Number), int64(orderItem.Number), RoundDown)
+		if err != nil {
+			return nil, err
+		}
+
+		amount, err := after.Sub(before)
+		if err != nil {
+			return nil, err
+		}
+
+		total, err := refundedAmount.Add(amount)
+		if err != nil {
+			return nil, err
+		}
+
+		remaining, err := paid.Sub(total)
+		if err != nil {
+			return nil, err
+		}
+
+		if remaining.IsNegative() {
+			return nil, fmt.Errorf("%w: product %s refund %s exceeds paid %s", ErrOrderInvalidReturn, line.ProductId, total, paid)
+		}
+
+		refunds = append(refunds, RefundLine{
+			ProductId: line.ProductId,
+			Number:    line.Number,
+			Amount:    amount,
+		})
+	}
+
+	return refunds, nil
+}
+
+func refundReturnItems(r Return, refunds []RefundLine) (Return, error) {
+	items := append([]ReturnItem(nil), r.Items...)
+	for _, refund := range refunds {
+		i := returnItemIndex(items, refund.ProductId)
+		if i < 0 {
+			return Return{}, fmt.Errorf("%w: product %s is not in return %s", ErrOrderInvalidReturn, refund.ProductId, r.ID)
+		}
+
+		amount := refund.Amount
+		if items[i].Refunded > 0 {
+			var err error
+			amount, err = items[i].RefundedAmount.Add(refund.Amount)
+			if err != nil {
+				return Return{}, err
+			}
+		}
+
+		items[i].Refunded += refund.Number
+		items[i].RefundedAmount = amount
+	}
+
+	r.Items = items
+	r.Status = returnStatus(items)
+
+	return r, nil
+}
+
+// paidAmount 订单项实付金额，历史订单没有税额明细时按订单项金额计算
+func (o *Order) paidAmount(orderItem OrderItem) (Money, error) {
+	for _, line := range o.Tax.Lines {
+		if line.ProductId == orderItem.ProductId {
+			return line.Gross, nil
+		}
+	}
+
+	return orderItem.GetTotal()
+}
+
+// refunded 订单项在所有退货单中已退款的数量和金额
+func (o *Order) refunded(productId string, currency string) (int32, Money, error) {
+	number := int32(0)
+	amount := NewMoney(0, currency)
+	for _, r := range o.Returns {
+		i := returnItemIndex(r.Items, productId)
+		if i < 0 || r.Items[i].Refunded == 0 {
+			continue
+		}
+
+		var err error
+		amount, err = amount.Add(r.Items[i].RefundedAmount)
+		if err != nil {
+			return 0, Money{}, err
+		}
+		number += r.Items[i].Refunded
+	}
+
+	return number, amount, nil
+}
+
+// returnStatus 所有订单项入库后为已入库，全部退款后为已退款
+func returnStatus(items []ReturnItem) ReturnStatus {
+	status := ReturnRefunded
+	for _, item := range items {
+		if item.Received < item.Requested {
+			return ReturnRequested
+		}
+		if item.Refunded < item.Requested {
+			status = ReturnReceived
+		}
+	}
+
+	return status
+}
+
+func returnItemIndex(items []ReturnItem, productId string) int {
+	for i, item := range items {
+		if item.ProductId == productId {
+			return i
+		}
+	}
+
+	return -1
+}
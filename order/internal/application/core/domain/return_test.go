@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"errors"
+	"order/internal/adapters/base"
+	"testing"
+	"time"
+)
+
+// testShippedOrder 创建已全部发货的订单：A 为 333 x 3 并有 10% 单项折扣，B 为 1000 x 1，整单再减 90，税率 13%
+func testShippedOrder(t *testing.T) *Order {
+	t.Helper()
+
+	o := NewOrder().(*Order)
+
+	commands := []base.Command{
+		&CreateOrder{
+			CustomerID: "c1",
+			OrderItems: []CreateOrderItem{
+				{ProductId: "A", Price: cny(333), Number: 3},
+				{ProductId: "B", Price: cny(1000), Number: 1},
+			},
+			TaxRates: TaxRates{Region: "CN", Rates: map[string]int64{DefaultTaxClass: 1300}},
+		},
+		&ApplyCoupon{Coupon: Coupon{Code: "P10A", Kind: PercentageCoupon, BasisPoints: 1000, ProductId: "A"}},
+		&ApplyCoupon{Coupon: Coupon{Code: "F90", Kind: FixedAmountCoupon, Amount: cny(90)}},
+		&ApproveOrder{},
+		&RecordShipment{Shipment: Shipment{
+			Carrier:        "sf",
+			TrackingNumber: "SF1",
+			Items:          []ShipmentItem{{ProductId: "A", Number: 3}, {ProductId: "B", Number: 1}},
+			ShippedAt:      time.Now(),
+		}},
+	}
+	for _, command := range commands {
+		err := testHandle(t, o, command)
+		if err != nil {
+			t.Fatalf("%s: %v", command.CommandName(), err)
+		}
+	}
+
+	return o
+}
+
+func testReturnLines(lines map[string]int32) []ReturnLine {
+	var returnLines []ReturnLine
+	for _, productId := range []string{"A", "B"} {
+		if number, ok := lines[productId]; ok {
+			returnLines = append(returnLines, ReturnLine{ProductId: productId, Number: number})
+		}
+	}
+
+	return returnLines
+}
+
+func TestRefundPaidAmount(t *testing.T) {
+	o := testShippedOrder(t)
+
+	paid := make(map[string]Money)
+	for _, line := range o.Tax.Lines {
+		paid[line.ProductId] = line.Gross
+	}
+
+	// A 折后 857，税 111；B 折后 952，税 124
+	if paid["A"] != cny(968) || paid["B"] != cny(1076) {
+		t.Fatalf("expected paid A 968 and B 1076, got %v", paid)
+	}
+
+	steps := []struct {
+		command *IssueRefund
+		amounts map[string]int64
+	}{
+		{&IssueRefund{ReturnID: "r1", ReasonCode: "damaged", Items: testReturnLines(map[string]int32{"A": 1})}, map[string]int64{"A": 322}},
+		{&IssueRefund{ReturnID: "r1", ReasonCode: "damaged", Items: testReturnLines(map[string]int32{"A": 1})}, map[string]int64{"A": 323}},
+		{&IssueRefund{ReturnID: "r2", ReasonCode: "damaged", Items: testReturnLines(map[string]int32{"A": 1, "B": 1})}, map[string]int64{"A": 323, "B": 1076}},
+	}
+
+	for _, r := range []struct {
+		id    string
+		lines map[string]int32
+	}{{"r1", map[string]int32{"A": 2}}, {"r2", map[string]int32{"A": 1, "B": 1}}} {
+		err := testHandle(t, o, &RequestReturn{ReturnID: r.id, ReasonCode: "damaged", Items: testReturnLines(r.lines)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = testHandle(t, o, &ReceiveReturn{ReturnID: r.id, ReasonCode: "damaged", Items: testReturnLines(r.lines)})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	refunded := map[string]Money{"A": cny(0), "B": cny(0)}
+	for _, step := range steps {
+		err := o.ProcessCommand(step.command)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		event := o.Events()[0].(*OrderRefundIssued)
+		total := cny(0)
+		for _, refund := range event.Items {
+			if refund.Amount != cny(step.amounts[refund.ProductId]) {
+				t.Fatalf("%s: expected refund %d, got %v", refund.ProductId, step.amounts[refund.ProductId], refund.Amount)
+			}
+
+			refunded[refund.ProductId], _ = refunded[refund.ProductId].Add(refund.Amount)
+			total, _ = total.Add(refund.Amount)
+		}
+		if event.Total != total {
+			t.Fatalf("expected refund total %v, got %v", total, event.Total)
+		}
+
+		err = o.ApplyEvent(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		o.ClearEvents()
+	}
+
+	// 多次部分退款累计恰好等于实付金额
+	if refunded["A"] != paid["A"] || refunded["B"] != paid["B"] {
+		t.Fatalf("expected refunds to add up to paid %v, got %v", paid, refunded)
+	}
+
+	for _, r := range o.Returns {
+		if r.Status != ReturnRefunded {
+			t.Fatalf("expected return %s refunded, got %s", r.ID, r.Status)
+		}
+	}
+
+	err := testHandle(t, o, &IssueRefund{ReturnID: "r2", ReasonCode: "damaged", Items: testReturnLines(map[string]int32{"A": 1})})
+	if !errors.Is(err, ErrOrderInvalidReturn) {
+		t.Fatalf("expected ErrOrderInvalidReturn for an extra refund, got %v", err)
+	}
+}
+
+func TestRefundUnevenSplit(t *testing.T) {
+	o := testShippedOrder(t)
+
+	err := testHandle(t, o, &RequestReturn{ReturnID: "r1", ReasonCode: "damaged", Items: testReturnLines(map[string]int32{"B": 1})})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 只入库未退款前不能退款
+	err = testHandle(t, o, &IssueRefund{ReturnID: "r1", ReasonCode: "damaged", Items: testReturnLines(map[string]int32{"B": 1})})
+	if !errors.Is(err, ErrOrderInvalidReturn) {
+		t.Fatalf("expected ErrOrderInvalidReturn before the return is received, got %v", err)
+	}
+
+	// 已申请退货的数量不能再次申请
+	err = testHandle(t, o, &RequestReturn{ReturnID: "r2", ReasonCode: "damaged", Items: testReturnLines(map[string]int32{"B": 1})})
+	if !errors.Is(err, ErrOrderInvalidReturn) {
+		t.Fatalf("expected ErrOrderInvalidReturn for an over-requested return, got %v", err)
+	}
+
+	// A 实付 968，单独退 1 件为 322、退 2 件为 645；先退 1 件再退 2 件时第二次为剩余的 646
+	err = testHandle(t, o, &RequestReturn{ReturnID: "r3", ReasonCode: "damaged", Items: testReturnLines(map[string]int32{"A": 3})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testHandle(t, o, &ReceiveReturn{ReturnID: "r3", ReasonCode: "damaged", Items: testReturnLines(map[string]int32{"A": 3})})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for number, amount := range map[int32]int64{1: 322, 2: 645} {
+		refunds, err := o.refundLines(o.Returns[len(o.Returns)-1], testReturnLines(map[string]int32{"A": number}))
+		if err != nil || refunds[0].Amount != cny(amount) {
+			t.Fatalf("expected first refund of %d to be %d, got %v %v", number, amount, refunds, err)
+		}
+	}
+
+	for _, number := range []int32{1, 2} {
+		err = testHandle(t, o, &IssueRefund{ReturnID: "r3", ReasonCode: "damaged", Items: testReturnLines(map[string]int32{"A": number})})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	i, _ := o.findReturn("r3")
+	if item := o.Returns[i].Items[0]; item.Refunded != 3 || item.RefundedAmount != cny(968) {
+		t.Fatalf("expected 3 refunded for 968, got %d for %v", item.Refunded, item.RefundedAmount)
+	}
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrOrderInvalidShipment = errors.New("order shipment is invalid")
+
+// Shipment 一个包裹的发货记录
+type Shipment struct {
+	Carrier        string         `json:"carrier"`
+	TrackingNumber string         `json:"tracking_number"`
+	Items          []ShipmentItem `json:"items"`
+	ShippedAt      time.Time      `json:"shipped_at"`
+}
+
+type ShipmentItem struct {
+	ProductId string `json:"product_id"`
+	Number    int32  `json:"number"`
+}
+
+func (s Shipment) validate(field string, violations *ValidationError) {
+	if strings.TrimSpace(s.Carrier) == "" {
+		violations.Add(field+".carrier", "must not be empty")
+	}
+	if strings.TrimSpace(s.TrackingNumber) == "" {
+		violations.Add(field+".tracking_number", "must not be empty")
+	}
+	if len(s.Items) == 0 {
+		violations.Add(field+".items", "must not be empty")
+	}
+
+	seen := make(map[string]int, len(s.Items))
+	for i, item := range s.Items {
+		itemField := fmt.Sprintf("%s.items[%d]", field, i)
+		if strings.TrimSpace(item.ProductId) == "" {
+			violations.Add(itemField+".product_id", "must not be empty")
+		} else if first, ok := seen[item.ProductId]; ok {
+			violations.Add(itemField+".product_id", "duplicates %s.items[%d]", field, first)
+		} else {
+			seen[item.ProductId] = i
+		}
+
+		if item.Number <= 0 {
+			violations.Add(itemField+".number", "must be greater than zero")
+		}
+	}
+}
+
+// shipItems 按发货数量累加各订单项的已发货数量，超出未发货数量时返回错误
+func (o *Order) shipItems(items []ShipmentItem) ([]OrderItem, error) {
+	shipped := make(map[string]int32, len(items))
+	for _, item := range items {
+		if !o.hasItem(item.ProductId) {
+			return nil, fmt.Errorf("%w: product %s is not in the order", ErrOrderInvalidShipment, item.ProductId)
+		}
+
+		shipped[item.ProductId] += item.Number
+	}
+
+	orderItems := make([]OrderItem, 0, len(o.OrderItems))
+	for _, orderItem := range o.OrderItems {
+		number := shipped[orderItem.ProductId]
+		if number > orderItem.Outstanding() {
+			return nil, fmt.Errorf("%w: product %s ships %d but only %d outstanding", ErrOrderInvalidShipment, orderItem.ProductId, number, orderItem.Outstanding())
+		}
+
+		orderItem.Shipped += number
+		orderItems = append(orderItems, orderItem)
+	}
+
+	return orderItems, nil
+}
+
+func fullyShipped(orderItems []OrderItem) bool {
+	for _, orderItem := range orderItems {
+		if orderItem.Outstanding() > 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultTaxClass 未指定税类的订单项按标准税率计税
+const DefaultTaxClass = "standard"
+
+var ErrTaxRateNotFound = errors.New("tax rate not found")
+
+// TaxRates 地区内各税类的税率，以万分比表示，1300 即 13%
+type TaxRates struct {
+	Region string           `json:"region"`
+	Rates  map[string]int64 `json:"rates"`
+}
+
+func (r TaxRates) rate(taxClass string) (int64, error) {
+	// 历史订单没有税率表，按免税处理
+	if r.Rates == nil {
+		return 0, nil
+	}
+
+	if taxClass == "" {
+		taxClass = DefaultTaxClass
+	}
+
+	rate, ok := r.Rates[taxClass]
+	if !ok {
+		return 0, fmt.Errorf("%w: region %q tax class %q", ErrTaxRateNotFound, r.Region, taxClass)
+	}
+
+	return rate, nil
+}
+
+// LineTax 订单项税额，Net 为分摊折扣后的不含税金额
+type LineTax struct {
+	ProductId string `json:"product_id"`
+	TaxClass  string `json:"tax_class"`
+	Rate      int64  `json:"rate"`
+	Net       Money  `json:"net"`
+	Tax       Money  `json:"tax"`
+	Gross     Money  `json:"gross"`
+}
+
+type TaxSummary struct {
+	Lines []LineTax `json:"lines"`
+	Net   Money     `json:"net"`
+	Tax   Money     `json:"tax"`
+	Gross Money     `json:"gross"`
+}
+
+// calculateTax 整单折扣按订单项折后金额比例分摊，余数计入最后一项
+func calculateTax(rates TaxRates, orderItems []OrderItem, pricing PriceBreakdown) (TaxSummary, error) {
+	nets := make([]Money, len(orderItems))
+	indexes := make(map[string]int, len(orderItems))
+	for i, orderItem := range orderItems {
+		lineTotal, err := orderItem.GetTotal()
+		if err != nil {
+			return TaxSummary{}, err
+		}
+
+		nets[i] = lineTotal
+		indexes[orderItem.ProductId] = i
+	}
+
+	orderDiscount := NewMoney(0, pricing.Total.Currency)
+	for _, discount := range pricing.Discounts {
+		var err error
+		if i, ok := indexes[discount.ProductId]; ok && discount.ProductId != "" {
+			nets[i], err = nets[i].Sub(discount.Amount)
+		} else {
+			orderDiscount, err = orderDiscount.Add(discount.Amount)
+		}
+		if err != nil {
+			return TaxSummary{}, err
+		}
+	}
+
+	lineNetTotal := NewMoney(0, pricing.Total.Currency)
+	for _, net := range nets {
+		var err error
+		lineNetTotal, err = lineNetTotal.Add(net)
+		if err != nil {
+			return TaxSummary{}, err
+		}
+	}
+
+	summary := TaxSummary{
+		Lines: make([]LineTax, 0, len(orderItems)),
+		Net:   NewMoney(0, pricing.Total.Currency),
+		Tax:   NewMoney(0, pricing.Total.Currency),
+		Gross: NewMoney(0, pricing.Total.Currency),
+	}
+
+	last := -1
+	for i, net := range nets {
+		if !net.IsZero() {
+			last = i
+		}
+	}
+
+	allocated := NewMoney(0, pricing.Total.Currency)
+	for i, orderItem := range orderItems {
+		share := NewMoney(0, pricing.Total.Currency)
+		var err error
+		if i == last {
+			share, err = orderDiscount.Sub(allocated)
+		} else if !lineNetTotal.IsZero() {
+			share, err = orderDiscount.MulRatio(nets[i].Amount, lineNetTotal.Amount, RoundDown)
+		}
+		if err != nil {
+			return TaxSummary{}, err
+		}
+
+		allocated, err = allocated.Add(share)
+		if err != nil {
+			return TaxSummary{}, err
+		}
+
+		net, err := nets[i].Sub(share)
+		if err != nil {
+			return TaxSummary{}, err
+		}
+
+		rate, err := rates.rate(orderItem.TaxClass)
+		if err != nil {
+			return TaxSummary{}, err
+		}
+
+		tax, err := net.MulRatio(rate, 10000, RoundHalfUp)
+		if err != nil {
+			return TaxSummary{}, err
+		}
+
+		gross, err := net.Add(tax)
+		if err != nil {
+			return TaxSummary{}, err
+		}
+
+		summary.Lines = append(summary.Lines, LineTax{
+			ProductId: orderItem.ProductId,
+			TaxClass:  orderItem.TaxClass,
+			Rate:      rate,
+			Net:       net,
+			Tax:       tax,
+			Gross:     gross,
+		})
+
+		if summary.Net, err = summary.Net.Add(net); err != nil {
+			return TaxSummary{}, err
+		}
+		if summary.Tax, err = summary.Tax.Add(tax); err != nil {
+			return TaxSummary{}, err
+		}
+		if summary.Gross, err = summary.Gross.Add(gross); err != nil {
+			return TaxSummary{}, err
+		}
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCalculateTax(t *testing.T) {
+	rates := TaxRates{Region: "CN", Rates: map[string]int64{"standard": 1300, "reduced": 900, "low": 1000}}
+
+	tests := []struct {
+		name       string
+		rates      *TaxRates
+		orderItems []OrderItem
+		coupons    []Coupon
+		nets       []int64
+		taxes      []int64
+		err        error
+	}{
+		{
+			name: "rounds per line",
+			orderItems: []OrderItem{
+				{ProductId: "A", Price: cny(5), Number: 1, TaxClass: "low"},
+				{ProductId: "B", Price: cny(5), Number: 1, TaxClass: "low"},
+			},
+			nets:  []int64{5, 5},
+			taxes: []int64{1, 1},
+		},
+		{
+			name: "order discount split by line net",
+			orderItems: []OrderItem{
+				{ProductId: "A", Price: cny(1000), Number: 1},
+				{ProductId: "B", Price: cny(333), Number: 3, TaxClass: "reduced"},
+				{ProductId: "C", Price: cny(1), Number: 1},
+			},
+			coupons: []Coupon{{Code: "F100", Kind: FixedAmountCoupon, Amount: cny(100)}},
+			// 50 与 49 向下取整，余数 1 计入最后一项
+			nets:  []int64{950, 950, 0},
+			taxes: []int64{124, 86, 0},
+		},
+		{
+			name: "order discount split after line discount",
+			orderItems: []OrderItem{
+				{ProductId: "A", Price: cny(1000), Number: 1},
+				{ProductId: "B", Price: cny(1000), Number: 1, TaxClass: "reduced"},
+			},
+			coupons: []Coupon{
+				{Code: "F200A", Kind: FixedAmountCoupon, Amount: cny(200), ProductId: "A"},
+				{Code: "F90", Kind: FixedAmountCoupon, Amount: cny(90)},
+			},
+			nets:  []int64{760, 950},
+			taxes: []int64{99, 86},
+		},
+		{
+			name: "remainder skips fully discounted line",
+			orderItems: []OrderItem{
+				{ProductId: "A", Price: cny(1000), Number: 1},
+				{ProductId: "B", Price: cny(500), Number: 1},
+			},
+			coupons: []Coupon{
+				{Code: "F500B", Kind: FixedAmountCoupon, Amount: cny(500), ProductId: "B"},
+				{Code: "F100", Kind: FixedAmountCoupon, Amount: cny(100)},
+			},
+			nets:  []int64{900, 0},
+			taxes: []int64{117, 0},
+		},
+		{
+			name:       "legacy order without rates is tax free",
+			rates:      &TaxRates{},
+			orderItems: []OrderItem{{ProductId: "A", Price: cny(1000), Number: 1}},
+			nets:       []int64{1000},
+			taxes:      []int64{0},
+		},
+		{
+			name:       "unknown tax class",
+			orderItems: []OrderItem{{ProductId: "A", Price: cny(1000), Number: 1, TaxClass: "luxury"}},
+			err:        ErrTaxRateNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taxRates := rates
+			if tt.rates != nil {
+				taxRates = *tt.rates
+			}
+
+			pricing, err := priceOrder(DefaultPricingRules, tt.orderItems, tt.coupons)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			summary, err := calculateTax(taxRates, tt.orderItems, pricing)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if tt.err != nil {
+				return
+			}
+
+			if len(summary.Lines) != len(tt.nets) {
+				t.Fatalf("expected %d lines, got %+v", len(tt.nets), summary.Lines)
+			}
+
+			net, tax, gross := cny(0), cny(0), cny(0)
+			for i, line := range summary.Lines {
+				if line.Net != cny(tt.nets[i]) || line.Tax != cny(tt.taxes[i]) {
+					t.Errorf("line %s: expected net %d tax %d, got %v %v", line.ProductId, tt.nets[i], tt.taxes[i], line.Net, line.Tax)
+				}
+				if sum, _ := line.Net.Add(line.Tax); line.Gross != sum {
+					t.Errorf("line %s: expected gross %v, got %v", line.ProductId, sum, line.Gross)
+				}
+
+				net, _ = net.Add(line.Net)
+				tax, _ = tax.Add(line.Tax)
+				gross, _ = gross.Add(line.Gross)
+			}
+
+			if summary.Net != net || summary.Tax != tax || summary.Gross != gross {
+				t.Fatalf("expected summary %v %v %v, got %+v", net, tax, gross, summary)
+			}
+			if summary.Net != pricing.Total {
+				t.Fatalf("expected net %v to equal order total, got %v", pricing.Total, summary.Net)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrOrderInvalid = errors.New("order is invalid")
+
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError 收集命令校验中的全部字段错误
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Add(field string, format string, args ...any) {
+	e.Violations = append(e.Violations, FieldViolation{
+		Field:       field,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+// Err 没有字段错误时返回 nil
+func (e *ValidationError) Err() error {
+	if len(e.Violations) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	violations := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		violations = append(violations, violation.Field+": "+violation.Description)
+	}
+
+	return fmt.Sprintf("%s: %s", ErrOrderInvalid, strings.Join(violations, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrOrderInvalid
+}
@@ -1,12 +1,50 @@
 package dto
 
+import (
+	"order/internal/application/core/domain"
+	"time"
+)
+
 type CreateOrderDTO struct {
-	CustomerID string
-	OrderItems []CreateOrderItemDTO
+	CustomerID      string
+	OrderItems      []CreateOrderItemDTO
+	DeliveryAddress domain.Address
+	TTL             time.Duration // 为 0 时使用默认超时时间
 }
 
 type CreateOrderItemDTO struct {
 	ProductId string
-	Price     float32
+	Price     domain.Money
+	Number    int32
+	TaxClass  string
+}
+
+type ReviseOrderDTO struct {
+	ItemDeltas []OrderItemDeltaDTO
+}
+
+type OrderItemDeltaDTO struct {
+	ProductId string
+	Delta     int32
+}
+
+type RecordShipmentDTO struct {
+	Carrier        string
+	TrackingNumber string
+	Items          []ShipmentItemDTO
+}
+
+type ShipmentItemDTO struct {
+	ProductId string
+	Number    int32
+}
+
+type ReturnDTO struct {
+	ReasonCode string
+	Items      []ReturnItemDTO
+}
+
+type ReturnItemDTO struct {
+	ProductId string
 	Number    int32
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/glebarez/sqlite"
 	"github.com/jinleibill/web-toolkit-go/egress"
 	"github.com/jinleibill/web-toolkit-go/grpc"
 	"golang.org/x/sync/errgroup"
@@ -18,9 +19,16 @@ import (
 
 type Service struct {
 	appFn          func(*Service) error
+	Storage        string
 	Conn           base.Client
 	AggregateStore base.Store
+	EventStore     *base.EventStore
+	Types          *base.TypeRegistry
+	Upcasters      *base.UpcasterRegistry
+	Keys           *base.KeyStore
+	Archive        *base.ArchiveStore // 内存存储不归档，为空
 	GrpcServer     grpc.Server
+	workers        []egress.WaiterFn
 }
 
 func NewService(appFn func(*Service) error) *Service {
@@ -28,18 +36,51 @@ func NewService(appFn func(*Service) error) *Service {
 }
 
 func (s *Service) Run() error {
-	dsn := config.GetDataSourceURL()
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	serializer, err := base.SerializerByCodec(config.GetEventCodec())
 	if err != nil {
 		return err
 	}
 
-	s.Conn = base.NewSessionClient(db)
-	s.AggregateStore = base.NewSnapshotStore(s.Conn)(base.NewEventStore(s.Conn))
+	// Types 和 Upcasters 在 appFn 中登记，加载事件和快照时生效
+	s.Types = base.NewTypeRegistry()
+	s.Upcasters = base.NewUpcasterRegistry()
+	s.Storage = config.GetStorageDriver()
+
+	switch s.Storage {
+	case config.MemoryStorage:
+		s.Conn = base.NewMemoryClient()
+		s.Keys = base.NewMemoryKeyStore()
+		s.EventStore = base.NewMemoryEventStore(s.eventStoreOptions(serializer)...)
+		s.AggregateStore = base.NewMemorySnapshotStore(s.snapshotStoreOptions(serializer)...)(s.EventStore)
+	case config.SQLiteStorage:
+		db, err := gorm.Open(sqlite.Open(config.GetSQLitePath()), &gorm.Config{})
+		if err != nil {
+			return err
+		}
+
+		// SQLite 同一时间只允许一个写事务，共用一个连接避免 database is locked
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		sqlDB.SetMaxOpenConns(1)
+
+		s.useSQLStores(db, serializer)
+	default:
+		db, err := gorm.Open(postgres.Open(config.GetDataSourceURL()), &gorm.Config{})
+		if err != nil {
+			return err
+		}
+
+		s.useSQLStores(db, serializer)
+	}
 
 	s.GrpcServer = grpc.NewServer(
 		fmt.Sprintf(":%d", config.GetApplicationPort()),
-		grpc.WithUnaryServerInterceptors(grpcServer.SessionUnaryInterceptor(s.Conn)),
+		grpc.WithUnaryServerInterceptors(
+			grpcServer.ErrorUnaryInterceptor(),
+			grpcServer.SessionUnaryInterceptor(s.Conn),
+		),
 	)
 
 	err = s.appFn(s)
@@ -51,9 +92,44 @@ func (s *Service) Run() error {
 
 	waiter.Add(s.waitForGrpcServer)
 
+	for _, worker := range s.workers {
+		waiter.Add(worker)
+	}
+
 	return waiter.Wait()
 }
 
+func (s *Service) useSQLStores(db *gorm.DB, serializer base.Serializer) {
+	s.Conn = base.NewSessionClient(db)
+	s.Keys = base.NewKeyStore(s.Conn)
+	s.EventStore = base.NewEventStore(s.Conn, s.eventStoreOptions(serializer)...)
+	s.Archive = base.NewArchiveStore(s.Conn, s.eventStoreOptions(serializer), s.snapshotStoreOptions(serializer))
+	s.AggregateStore = s.Archive.Middleware(base.NewSnapshotStore(s.Conn, s.snapshotStoreOptions(serializer)...)(s.EventStore))
+}
+
+func (s *Service) eventStoreOptions(serializer base.Serializer) []base.EventStoreOption {
+	return []base.EventStoreOption{
+		base.WithEventTypes(s.Types),
+		base.WithEventKeyStore(s.Keys),
+		base.WithEventUpcasters(s.Upcasters),
+		base.WithEventSerializer(serializer),
+	}
+}
+
+func (s *Service) snapshotStoreOptions(serializer base.Serializer) []base.SnapshotStoreOption {
+	return []base.SnapshotStoreOption{
+		base.WithSnapshotStoreTypes(s.Types),
+		base.WithSnapshotStoreKeyStore(s.Keys),
+		base.WithSnapshotStoreUpcasters(s.Upcasters),
+		base.WithSnapshotStoreSerializer(serializer),
+	}
+}
+
+// AddWorker 注册后台任务，随服务启动，收到退出信号时 ctx 被取消
+func (s *Service) AddWorker(worker egress.WaiterFn) {
+	s.workers = append(s.workers, worker)
+}
+
 func (s *Service) waitForGrpcServer(ctx context.Context) (err error) {
 	group, gCtx := errgroup.WithContext(ctx)
 
@@ -4,9 +4,31 @@ import (
 	"context"
 	"order/internal/application/core/domain"
 	"order/internal/application/core/dto"
+	"time"
 )
 
 type Application interface {
 	GetOrder(ctx context.Context, aggregateID string) (*domain.Order, error)
+	GetOrderAtVersion(ctx context.Context, aggregateID string, version int) (*domain.Order, error)
+	GetOrderAsOf(ctx context.Context, aggregateID string, asOf time.Time) (*domain.Order, error)
 	CreateOrder(ctx context.Context, dto dto.CreateOrderDTO) (domain.Order, error)
+	ApproveOrder(ctx context.Context, aggregateID string) error
+	RejectOrder(ctx context.Context, aggregateID string, reason string) error
+	ExpireOrder(ctx context.Context, aggregateID string, now time.Time) error
+	BeginCancelOrder(ctx context.Context, aggregateID string) error
+	ConfirmCancelOrder(ctx context.Context, aggregateID string) error
+	UndoCancelOrder(ctx context.Context, aggregateID string) error
+	ReviseOrder(ctx context.Context, aggregateID string, dto dto.ReviseOrderDTO) (domain.Order, error)
+	ConfirmRevision(ctx context.Context, aggregateID string) error
+	RejectRevision(ctx context.Context, aggregateID string) error
+	ChangeDeliveryAddress(ctx context.Context, aggregateID string, address domain.Address) error
+	ApplyCoupon(ctx context.Context, aggregateID string, coupon domain.Coupon) (domain.Order, error)
+	RemoveCoupon(ctx context.Context, aggregateID string, code string) (domain.Order, error)
+	RecordShipment(ctx context.Context, aggregateID string, dto dto.RecordShipmentDTO) (domain.Order, error)
+	RequestReturn(ctx context.Context, aggregateID string, dto dto.ReturnDTO) (string, error)
+	ReceiveReturn(ctx context.Context, aggregateID string, returnID string, dto dto.ReturnDTO) error
+	IssueRefund(ctx context.Context, aggregateID string, returnID string, dto dto.ReturnDTO) (domain.Return, error)
+	ForgetCustomer(ctx context.Context, customerID string) error
+	ArchiveOrder(ctx context.Context, aggregateID string, version int) (bool, error)
+	RestoreOrder(ctx context.Context, aggregateID string) error
 }
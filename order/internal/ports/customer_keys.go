@@ -0,0 +1,7 @@
+package ports
+
+import "context"
+
+type CustomerKeyRepository interface {
+	Forget(ctx context.Context, customerID string) error
+}
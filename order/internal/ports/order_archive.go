@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"order/internal/adapters/base"
+	"time"
+)
+
+type OrderArchiveRepository interface {
+	// FindClosed 按 ID 顺序返回 afterID 之后、before 之前已结束的订单，结果仍需按订单状态确认
+	FindClosed(ctx context.Context, before time.Time, afterID string, limit int) ([]base.StreamVersion, error)
+	// Archive 订单已不在 version 时不归档并返回 false
+	Archive(ctx context.Context, orderID string, version int) (bool, error)
+	Restore(ctx context.Context, orderID string) error
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+type OrderDeadlineRepository interface {
+	Schedule(ctx context.Context, orderID string, deadline time.Time) error
+	NextDue(ctx context.Context, now time.Time) (orderID string, ok bool, err error)
+	Remove(ctx context.Context, orderID string) error
+}
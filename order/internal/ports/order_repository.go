@@ -4,9 +4,13 @@ import (
 	"context"
 	"order/internal/adapters/base"
 	"order/internal/application/core/domain"
+	"time"
 )
 
 type OrderRepository interface {
 	Load(ctx context.Context, aggregateID string) (*domain.Order, error)
+	LoadAtVersion(ctx context.Context, aggregateID string, version int) (*domain.Order, error)
+	LoadAsOf(ctx context.Context, aggregateID string, asOf time.Time) (*domain.Order, error)
 	Save(ctx context.Context, command base.Command, options ...base.AggregateRootOption) (*domain.Order, error)
+	Execute(ctx context.Context, aggregateID string, command base.Command, options ...base.AggregateRootOption) (*domain.Order, error)
 }
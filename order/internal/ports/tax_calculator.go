@@ -0,0 +1,10 @@
+package ports
+
+import (
+	"context"
+	"order/internal/application/core/domain"
+)
+
+type TaxCalculator interface {
+	TaxRates(ctx context.Context, region string) (domain.TaxRates, error)
+}